@@ -0,0 +1,356 @@
+// Package api serves stored server updates, and their items, over an HTTP JSON API, so that
+// dashboards do not each need Ros-Bot credentials.
+//
+//	GET /updates?since=&until=&rarity=&quality=&destination=&filter=&limit=&offset=
+//	GET /items/{id}
+//	GET /stats?bucket=&group_by=, and the filters of '/updates'
+//	GET /healthz
+//
+// Filters follow the semantics of `rosbotcollector.ParserConfig`: `rarity` is the minimum rarity,
+// `quality` one of "all", "normal" or "set", and `destination` a comma-separated list, none
+// meaning all of them. `filter` is a query, see `rosbotcollector.ParseQuery`.
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	rbc "github.com/maxzaleski/go-rosbot-collector"
+	"github.com/maxzaleski/go-rosbot-collector/stats"
+	"github.com/maxzaleski/go-rosbot-collector/store"
+)
+
+const (
+	// DefaultLimit is the number of server updates returned when `limit` is not set.
+	DefaultLimit = 100
+	// MaxLimit is the maximum value of `limit`.
+	MaxLimit = 1000
+)
+
+// ErrInvalidParameter is returned when a query parameter cannot be parsed.
+var ErrInvalidParameter = errors.New("invalid parameter")
+
+type (
+	// Handler serves the API; it implements `http.Handler`.
+	Handler struct {
+		store  store.Store
+		health func(ctx context.Context) error
+		mux    *http.ServeMux
+	}
+
+	// Option configures a `Handler`.
+	Option func(h *Handler)
+)
+
+// WithHealthCheck sets the check of '/healthz', i.e. whether the collector polls successfully.
+// A returned error is served with a 503 status.
+func WithHealthCheck(check func(ctx context.Context) error) Option {
+	return func(h *Handler) {
+		h.health = check
+	}
+}
+
+// NewHandler returns a new instance of `api.Handler` serving the content of the store.
+func NewHandler(s store.Store, opts ...Option) *Handler {
+	h := &Handler{store: s, mux: http.NewServeMux()}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.mux.HandleFunc("/updates", h.updates)
+	h.mux.HandleFunc("/items/", h.item)
+	h.mux.HandleFunc("/stats", h.stats)
+	h.mux.HandleFunc("/healthz", h.healthz)
+	return h
+}
+
+// ServeHTTP implements `http.Handler`.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) updates(w http.ResponseWriter, r *http.Request) {
+	f, err := parseFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	updates, err := f.updates(r.Context(), h.store)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if updates == nil {
+		updates = []*rbc.ServerUpdate{}
+	}
+	writeJSON(w, http.StatusOK, updates)
+}
+
+func (h *Handler) item(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/items/")
+	if id == "" || strings.Contains(id, "/") {
+		writeError(w, http.StatusNotFound, store.ErrNotFound)
+		return
+	}
+	item, err := h.store.Item(r.Context(), id)
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		writeError(w, http.StatusNotFound, fmt.Errorf("item %q: %w", id, err))
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+	default:
+		writeJSON(w, http.StatusOK, item)
+	}
+}
+
+func (h *Handler) stats(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+	f, err := parseFilter(v)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	config := &stats.Config{From: f.query.From, To: f.query.To}
+	if s := v.Get("bucket"); s != "" {
+		if config.Bucket, err = time.ParseDuration(s); err != nil || config.Bucket < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("%w bucket %q: must be a positive duration, i.e. \"1h\"", ErrInvalidParameter, s))
+			return
+		}
+	}
+	if config.GroupBy, err = stats.ParseDimensions(v.Get("group_by")); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Every matching update is aggregated; `limit` and `offset` do not apply.
+	f.query.Limit, f.query.Offset, f.limit, f.offset = 0, 0, 0, 0
+	updates, err := f.updates(r.Context(), h.store)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	report, err := stats.Aggregate(updates, config)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newStatsResponse(report))
+}
+
+func (h *Handler) healthz(w http.ResponseWriter, r *http.Request) {
+	if h.health != nil {
+		if err := h.health(r.Context()); err != nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "unavailable", "error": err.Error()})
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// filter holds the parsed filters of a request. The client-side `predicate` cannot be evaluated
+// by the store; when set, the store is queried without paging, which is applied afterwards.
+type filter struct {
+	query         store.Query
+	predicate     rbc.Predicate
+	limit, offset int
+}
+
+// ParseConfig parses the `rarity`, `quality`, `destination` and `filter` parameters into a
+// parsing configuration. Missing parameters keep the values of `rosbotcollector.NewParseConfig`.
+func ParseConfig(v url.Values) (*rbc.ParserConfig, error) {
+	c := rbc.NewParseConfig()
+	for _, list := range v["destination"] {
+		for _, d := range strings.Split(list, ",") {
+			if d = strings.TrimSpace(d); d != "" {
+				c.Destinations = append(c.Destinations, rbc.Destination(strings.ToUpper(d)))
+			}
+		}
+	}
+	switch s := strings.ToLower(v.Get("rarity")); s {
+	case "", "non-ancient", "nonancient":
+	default:
+		c.MinRarity(rbc.Rarity(strings.ToUpper(s)))
+	}
+	switch s := strings.ToLower(v.Get("quality")); s {
+	case "", "all", "*":
+	case "legendary":
+		c.WithQuality(rbc.QualityNormal)
+	default:
+		c.WithQuality(rbc.Quality(strings.ToUpper(s)))
+	}
+	if s := v.Get("filter"); s != "" {
+		p, err := rbc.ParseQuery(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w filter: %v", ErrInvalidParameter, err)
+		}
+		c.WithFilter(p)
+	}
+	return c, c.Validate()
+}
+
+func parseFilter(v url.Values) (*filter, error) {
+	c, err := ParseConfig(v)
+	if err != nil {
+		return nil, err
+	}
+	f := &filter{
+		query: store.Query{
+			Destinations: c.Destinations,
+			MinRarity:    c.RarityLevel,
+			Name:         v.Get("name"),
+			Bot:          v.Get("bot"),
+		},
+		predicate: c.Filter,
+		limit:     DefaultLimit,
+	}
+	if c.Quality != rbc.QualityAll {
+		f.query.Qualities = []rbc.Quality{c.Quality}
+	}
+	if f.query.From, err = parseTime(v, "since"); err != nil {
+		return nil, err
+	}
+	if f.query.To, err = parseTime(v, "until"); err != nil {
+		return nil, err
+	}
+	if f.limit, err = parseInt(v, "limit", DefaultLimit, MaxLimit); err != nil {
+		return nil, err
+	}
+	if f.offset, err = parseInt(v, "offset", 0, -1); err != nil {
+		return nil, err
+	}
+	if f.predicate == nil {
+		f.query.Limit, f.query.Offset = f.limit, f.offset
+	}
+	return f, nil
+}
+
+// updates returns the matching server updates, oldest first.
+func (f *filter) updates(ctx context.Context, s store.Store) ([]*rbc.ServerUpdate, error) {
+	updates, err := s.Updates(ctx, &f.query)
+	if err != nil || f.predicate == nil {
+		return updates, err
+	}
+	updates = rbc.FilterUpdates(updates, f.predicate)
+	start, end := f.offset, len(updates)
+	if start > end {
+		start = end
+	}
+	if f.limit > 0 && start+f.limit < end {
+		end = start + f.limit
+	}
+	return updates[start:end], nil
+}
+
+// parseTime parses an RFC 3339 timestamp, i.e. "2020-05-01T12:00:00Z".
+func parseTime(v url.Values, key string) (time.Time, error) {
+	s := v.Get(key)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w %s %q: must be an RFC 3339 timestamp, i.e. \"2020-05-01T12:00:00Z\"", ErrInvalidParameter, key, s)
+	}
+	return t, nil
+}
+
+// parseInt parses a non-negative integer, capped at `max` unless it is negative.
+func parseInt(v url.Values, key string, def, max int) (int, error) {
+	s := v.Get(key)
+	if s == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%w %s %q: must be a non-negative integer", ErrInvalidParameter, key, s)
+	}
+	if max >= 0 && n > max {
+		return max, nil
+	}
+	return n, nil
+}
+
+type (
+	statsResponse struct {
+		From    time.Time         `json:"from"`
+		To      time.Time         `json:"to"`
+		GroupBy []stats.Dimension `json:"group_by"`
+		Buckets []time.Time       `json:"buckets"`
+		Totals  []int             `json:"totals"`
+		Total   int               `json:"total"`
+		Skipped int               `json:"skipped"`
+		Groups  []*groupResponse  `json:"groups"`
+	}
+
+	groupResponse struct {
+		Key     map[stats.Dimension]string `json:"key"`
+		Count   int                        `json:"count"`
+		Buckets []int                      `json:"buckets"`
+		// Intervals are in seconds.
+		Intervals struct {
+			Count int     `json:"count"`
+			Mean  float64 `json:"mean"`
+			Min   float64 `json:"min"`
+			P50   float64 `json:"p50"`
+			P90   float64 `json:"p90"`
+			Max   float64 `json:"max"`
+		} `json:"intervals"`
+	}
+)
+
+func newStatsResponse(r *stats.Report) *statsResponse {
+	res := &statsResponse{
+		From:    r.From,
+		To:      r.To,
+		GroupBy: r.GroupBy,
+		Buckets: r.Buckets,
+		Totals:  r.Totals,
+		Total:   r.Total,
+		Skipped: r.Skipped,
+		Groups:  []*groupResponse{},
+	}
+	if res.GroupBy == nil {
+		res.GroupBy = []stats.Dimension{}
+	}
+	if res.Buckets == nil {
+		res.Buckets, res.Totals = []time.Time{}, []int{}
+	}
+	for _, g := range r.Groups {
+		gr := &groupResponse{Key: make(map[stats.Dimension]string, len(g.Key)), Count: g.Count, Buckets: g.Buckets}
+		for i, d := range r.GroupBy {
+			gr.Key[d] = g.Key[i]
+		}
+		in := g.Intervals
+		gr.Intervals.Count = in.Count()
+		gr.Intervals.Mean = in.Mean().Seconds()
+		gr.Intervals.Min = in.Min().Seconds()
+		gr.Intervals.P50 = in.Percentile(50).Seconds()
+		gr.Intervals.P90 = in.Percentile(90).Seconds()
+		gr.Intervals.Max = in.Max().Seconds()
+		res.Groups = append(res.Groups, gr)
+	}
+	return res
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
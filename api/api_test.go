@@ -0,0 +1,173 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	rbc "github.com/maxzaleski/go-rosbot-collector"
+	"github.com/maxzaleski/go-rosbot-collector/store"
+	"github.com/maxzaleski/go-rosbot-collector/store/storetest"
+)
+
+func newTestHandler(t *testing.T, opts ...Option) *Handler {
+	t.Helper()
+	s := store.NewMemory()
+	if err := s.SaveUpdates(context.Background(), storetest.Updates()); err != nil {
+		t.Fatal(err)
+	}
+	return NewHandler(s, opts...)
+}
+
+// get serves the request, and decodes the JSON response into `v`.
+func get(t *testing.T, h http.Handler, target string, v interface{}) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("invalid response %q: %v", rec.Body.String(), err)
+	}
+	return rec.Code
+}
+
+func TestHandler_updates(t *testing.T) {
+	h := newTestHandler(t)
+	tests := []struct {
+		target     string
+		wantStatus int
+		wantItems  []string
+	}{
+		{target: "/updates", wantStatus: http.StatusOK, wantItems: []string{"u3-0", "u1-0", "u1-1", "u2-0"}},
+		{target: "/updates?since=2020-05-01T10:30:00Z", wantStatus: http.StatusOK, wantItems: []string{"u2-0"}},
+		{target: "/updates?rarity=ancient", wantStatus: http.StatusOK, wantItems: []string{"u1-0", "u2-0"}},
+		{target: "/updates?quality=set", wantStatus: http.StatusOK, wantItems: []string{"u1-1"}},
+		{target: "/updates?destination=stashed,sold", wantStatus: http.StatusOK, wantItems: []string{"u3-0", "u1-0", "u2-0"}},
+		{target: "/updates?destination=sold&destination=salvaged", wantStatus: http.StatusOK, wantItems: []string{"u3-0", "u1-1"}},
+		{target: "/updates?bot=wiz&limit=1", wantStatus: http.StatusOK, wantItems: []string{"u3-0"}},
+		{target: "/updates?filter=" + url.QueryEscape(`name~"r"`) + "&offset=1", wantStatus: http.StatusOK, wantItems: []string{"u1-0", "u2-0"}},
+		{target: "/updates?rarity=primal&bot=barb", wantStatus: http.StatusOK, wantItems: nil},
+		{target: "/updates?rarity=legendary", wantStatus: http.StatusBadRequest},
+		{target: "/updates?destination=lost", wantStatus: http.StatusBadRequest},
+		{target: "/updates?since=yesterday", wantStatus: http.StatusBadRequest},
+		{target: "/updates?limit=-1", wantStatus: http.StatusBadRequest},
+		{target: "/updates?filter=rarity%3E", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			if tt.wantStatus != http.StatusOK {
+				var res map[string]string
+				if status := get(t, h, tt.target, &res); status != tt.wantStatus || res["error"] == "" {
+					t.Errorf("GET %s = %d %v, want %d and an error", tt.target, status, res, tt.wantStatus)
+				}
+				return
+			}
+
+			var updates []*rbc.ServerUpdate
+			if status := get(t, h, tt.target, &updates); status != tt.wantStatus {
+				t.Fatalf("GET %s = %d, want %d", tt.target, status, tt.wantStatus)
+			}
+			var items []string
+			for _, u := range updates {
+				for _, item := range u.Items {
+					items = append(items, item.ID)
+				}
+			}
+			if !reflect.DeepEqual(items, tt.wantItems) {
+				t.Errorf("GET %s = %v, want %v", tt.target, items, tt.wantItems)
+			}
+		})
+	}
+}
+
+func TestHandler_item(t *testing.T) {
+	h := newTestHandler(t)
+
+	var item rbc.ItemRecord
+	if status := get(t, h, "/items/u2-0", &item); status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	if item.UpdateID != "u2" || item.Name != "Stone of Jordan" || item.Rarity != rbc.RarityPrimal {
+		t.Errorf("item = %+v", item)
+	}
+
+	var res map[string]string
+	if status := get(t, h, "/items/u9-0", &res); status != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", status)
+	}
+}
+
+func TestHandler_stats(t *testing.T) {
+	h := newTestHandler(t)
+
+	var res statsResponse
+	if status := get(t, h, "/stats?group_by=bot&bucket=1h&rarity=ancient", &res); status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	if res.Total != 2 || !reflect.DeepEqual(res.Totals, []int{1, 1}) {
+		t.Errorf("Total, Totals = %d, %v, want 2, [1 1]", res.Total, res.Totals)
+	}
+	if len(res.Groups) != 2 || res.Groups[0].Key["bot"] != "Barb" || res.Groups[1].Key["bot"] != "Wiz" {
+		t.Errorf("Groups = %+v", res.Groups)
+	}
+
+	var bad map[string]string
+	for _, target := range []string{"/stats?group_by=colour", "/stats?bucket=soon"} {
+		if status := get(t, h, target, &bad); status != http.StatusBadRequest {
+			t.Errorf("GET %s = %d, want 400", target, status)
+		}
+	}
+}
+
+func TestHandler_healthz(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []Option
+		wantStatus int
+	}{
+		{name: "no check", wantStatus: http.StatusOK},
+		{name: "healthy", opts: []Option{WithHealthCheck(func(context.Context) error { return nil })}, wantStatus: http.StatusOK},
+		{name: "unhealthy", opts: []Option{WithHealthCheck(func(context.Context) error { return errors.New("stale") })}, wantStatus: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res map[string]string
+			if status := get(t, newTestHandler(t, tt.opts...), "/healthz", &res); status != tt.wantStatus {
+				t.Errorf("status = %d %v, want %d", status, res, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestHandler_methodNotAllowed(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newTestHandler(t).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/updates", nil))
+	if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") == "" {
+		t.Errorf("POST /updates = %d, Allow %q", rec.Code, rec.Header().Get("Allow"))
+	}
+}
+
+func Test_ParseConfig(t *testing.T) {
+	got, err := ParseConfig(url.Values{"rarity": {"Ancient"}, "quality": {"legendary"}, "destination": {"stashed, sold"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := rbc.NewParseConfig().
+		MinRarity(rbc.RarityAncient).
+		WithQuality(rbc.QualityNormal).
+		WithDestinations(rbc.DestinationStashed, rbc.DestinationSold)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseConfig() = %+v, want %+v", got, want)
+	}
+
+	if _, err := ParseConfig(url.Values{"quality": {"magic"}}); !errors.Is(err, rbc.ErrInvalidQuality) {
+		t.Errorf("ParseConfig() error = %v, want %v", err, rbc.ErrInvalidQuality)
+	}
+}
@@ -0,0 +1,303 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	rbc "github.com/maxzaleski/go-rosbot-collector"
+)
+
+const (
+	// DefaultHeartbeat is the delay between two heartbeats of a feed.
+	DefaultHeartbeat = 15 * time.Second
+	// DefaultReplayCapacity is the number of server updates a feed keeps for resumption.
+	DefaultReplayCapacity = 1000
+)
+
+// subscriberBuffer is the number of server updates queued for a client; a client lagging further
+// behind is disconnected, and resumes from its last event ID.
+const subscriberBuffer = 64
+
+type (
+	// Feed pushes newly collected server updates to its clients over Server-Sent Events. It
+	// implements `http.Handler`; `Publish` is the `rosbotcollector.UpdateHandler` feeding it.
+	//
+	//	GET /events?rarity=&quality=&destination=&filter=&mode=updates|items
+	//
+	// Clients are filtered as by '/updates'. In "updates" mode, the default, every event is an
+	// "update" holding the matching items of a server update; in "items" mode, every matching
+	// item is an "item" event holding an `rosbotcollector.ItemRecord`. Reconnecting clients
+	// resume after their `Last-Event-ID` header, or `last_event_id` parameter, from the updates
+	// kept by the feed.
+	Feed struct {
+		heartbeat time.Duration
+		capacity  int
+
+		mu          sync.Mutex
+		seq         uint64
+		events      []*feedEvent
+		subscribers map[chan *feedEvent]struct{}
+	}
+
+	// FeedOption configures a `Feed`.
+	FeedOption func(f *Feed)
+
+	feedEvent struct {
+		seq    uint64
+		update *rbc.ServerUpdate
+	}
+)
+
+// WithHeartbeat sets the delay between two heartbeats, which keep idle connections open through
+// proxies. Defaults to `DefaultHeartbeat`.
+func WithHeartbeat(d time.Duration) FeedOption {
+	return func(f *Feed) {
+		f.heartbeat = d
+	}
+}
+
+// WithReplayCapacity sets the number of server updates kept for resumption. Defaults to
+// `DefaultReplayCapacity`.
+func WithReplayCapacity(n int) FeedOption {
+	return func(f *Feed) {
+		f.capacity = n
+	}
+}
+
+// NewFeed returns a new instance of `api.Feed`.
+func NewFeed(opts ...FeedOption) *Feed {
+	f := &Feed{
+		heartbeat:   DefaultHeartbeat,
+		capacity:    DefaultReplayCapacity,
+		subscribers: make(map[chan *feedEvent]struct{}),
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	if f.heartbeat <= 0 {
+		f.heartbeat = DefaultHeartbeat
+	}
+	return f
+}
+
+// Publish pushes the server update to the connected clients; it satisfies
+// `rosbotcollector.UpdateHandler`.
+func (f *Feed) Publish(_ context.Context, u *rbc.ServerUpdate) error {
+	c := *u
+	c.Items = append([]*rbc.LegendaryItem(nil), u.Items...)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.seq++
+	ev := &feedEvent{seq: f.seq, update: &c}
+	if f.capacity > 0 {
+		if len(f.events) == f.capacity {
+			f.events = f.events[1:]
+		}
+		f.events = append(f.events, ev)
+	}
+	for ch := range f.subscribers {
+		select {
+		case ch <- ev:
+		default:
+			// The client lags behind; it is disconnected rather than slowing every other one.
+			delete(f.subscribers, ch)
+			close(ch)
+		}
+	}
+	return nil
+}
+
+// ServeHTTP implements `http.Handler`.
+func (f *Feed) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	v := r.URL.Query()
+	config, err := ParseConfig(v)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	items := false
+	switch mode := v.Get("mode"); mode {
+	case "", "updates":
+	case "items":
+		items = true
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("%w mode %q: must be \"updates\" or \"items\"", ErrInvalidParameter, mode))
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming is not supported"))
+		return
+	}
+
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = v.Get("last_event_id")
+	}
+	ch, replay, after := f.subscribe(parseEventID(lastID))
+	defer f.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	s := &stream{w: w, match: config.Predicate(), items: items, after: after}
+	for _, ev := range replay {
+		if err := s.write(ev); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(f.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case ev, ok := <-ch:
+			if !ok {
+				return
+			}
+			if err := s.write(ev); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// subscribe registers a client, and returns the kept updates following the last event ID it
+// received, alongside the ID it resumes after. Both happen under the lock, so that no update is
+// missed, nor sent twice, in between.
+func (f *Feed) subscribe(after eventID) (chan *feedEvent, []*feedEvent, eventID) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ch := make(chan *feedEvent, subscriberBuffer)
+	f.subscribers[ch] = struct{}{}
+	if after.seq == 0 {
+		return ch, nil, after
+	}
+	// IDs restart with the feed; an unknown ID replays every kept update.
+	if after.seq > f.seq {
+		return ch, append([]*feedEvent(nil), f.events...), eventID{}
+	}
+	var replay []*feedEvent
+	for _, ev := range f.events {
+		if ev.seq >= after.seq {
+			replay = append(replay, ev)
+		}
+	}
+	return ch, replay, after
+}
+
+func (f *Feed) unsubscribe(ch chan *feedEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.subscribers[ch]; ok {
+		delete(f.subscribers, ch)
+		close(ch)
+	}
+}
+
+// subscriberCount returns the number of connected clients.
+func (f *Feed) subscriberCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.subscribers)
+}
+
+// eventID is the position of an event: the sequence number of its server update and, in "items"
+// mode, the index of its item. Events are written with IDs "seq" or "seq-index".
+type eventID struct {
+	seq   uint64
+	index int
+	// item is set when the ID holds an item index.
+	item bool
+}
+
+// parseEventID returns the zero ID when `s` is not a valid ID.
+func parseEventID(s string) eventID {
+	seq, index := s, ""
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		seq, index = s[:i], s[i+1:]
+	}
+	id := eventID{}
+	n, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil {
+		return eventID{}
+	}
+	id.seq = n
+	if index != "" {
+		if id.index, err = strconv.Atoi(index); err != nil {
+			return eventID{}
+		}
+		id.item = true
+	}
+	return id
+}
+
+// stream writes the events of a client.
+type stream struct {
+	w     http.ResponseWriter
+	match rbc.Predicate
+	items bool
+	// after is the last event ID received by the client; events up to it are not written.
+	after eventID
+}
+
+func (s *stream) write(ev *feedEvent) error {
+	if ev.seq < s.after.seq || (ev.seq == s.after.seq && !s.after.item) {
+		return nil
+	}
+	updates := rbc.FilterUpdates([]*rbc.ServerUpdate{ev.update}, s.match)
+	if len(updates) == 0 {
+		return nil
+	}
+	if !s.items {
+		return writeEvent(s.w, strconv.FormatUint(ev.seq, 10), "update", updates[0])
+	}
+	for _, item := range ev.update.Items {
+		if !s.match(ev.update, item) {
+			continue
+		}
+		index := item.Index
+		if ev.seq == s.after.seq && index <= s.after.index {
+			continue
+		}
+		r := &rbc.ItemRecord{UpdateID: ev.update.ID, ServerTimestamp: ev.update.ServerTimestamp, LegendaryItem: item}
+		if err := writeEvent(s.w, fmt.Sprintf("%d-%d", ev.seq, index), "item", r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeEvent(w http.ResponseWriter, id, event string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", id, event, b)
+	return err
+}
@@ -0,0 +1,236 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	rbc "github.com/maxzaleski/go-rosbot-collector"
+	"github.com/maxzaleski/go-rosbot-collector/store/storetest"
+)
+
+// event is a received Server-Sent Event; heartbeats have a "heartbeat" comment.
+type event struct {
+	id, name, data, comment string
+}
+
+// connect opens a feed stream; events are received on the returned channel until the context is
+// cancelled.
+func connect(ctx context.Context, t *testing.T, url, lastEventID string) <-chan event {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	req = req.WithContext(ctx)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != http.StatusOK || res.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("GET %s = %d %s", url, res.StatusCode, res.Header.Get("Content-Type"))
+	}
+
+	events := make(chan event, 16)
+	go func() {
+		defer res.Body.Close()
+		defer close(events)
+		sc := bufio.NewScanner(res.Body)
+		ev := event{}
+		for sc.Scan() {
+			line := sc.Text()
+			switch {
+			case line == "":
+				events <- ev
+				ev = event{}
+			case strings.HasPrefix(line, ": "):
+				ev.comment = line[2:]
+			case strings.HasPrefix(line, "id: "):
+				ev.id = line[4:]
+			case strings.HasPrefix(line, "event: "):
+				ev.name = line[7:]
+			case strings.HasPrefix(line, "data: "):
+				ev.data = line[6:]
+			}
+		}
+	}()
+	return events
+}
+
+// receive returns the next `n` events which are not heartbeats.
+func receive(t *testing.T, events <-chan event, n int) []event {
+	t.Helper()
+	var got []event
+	timeout := time.After(5 * time.Second)
+	for len(got) < n {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				t.Fatalf("stream closed after %d events, want %d", len(got), n)
+			}
+			if ev.comment == "" {
+				got = append(got, ev)
+			}
+		case <-timeout:
+			t.Fatalf("received %d events, want %d", len(got), n)
+		}
+	}
+	return got
+}
+
+// waitSubscribers waits for `n` clients to be connected, so that published updates reach them.
+func waitSubscribers(t *testing.T, f *Feed, n int) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); f.subscriberCount() != n; {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d subscribers, want %d", f.subscriberCount(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func publish(t *testing.T, f *Feed, updates ...*rbc.ServerUpdate) {
+	t.Helper()
+	for _, u := range updates {
+		if err := f.Publish(context.Background(), u); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func ids(events []event) []string {
+	var ids []string
+	for _, ev := range events {
+		ids = append(ids, ev.name+" "+ev.id)
+	}
+	return ids
+}
+
+func TestFeed(t *testing.T) {
+	f := NewFeed()
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	all := connect(ctx, t, srv.URL, "")
+	ancient := connect(ctx, t, srv.URL+"?rarity=ancient&mode=items", "")
+	waitSubscribers(t, f, 2)
+	publish(t, f, storetest.Updates()...)
+
+	got := receive(t, all, 3)
+	if want := "update 1,update 2,update 3"; strings.Join(ids(got), ",") != want {
+		t.Errorf("all = %v, want %s", ids(got), want)
+	}
+	if !strings.Contains(got[0].data, `"legendaries":[{`) || !strings.Contains(got[0].data, `"Unidentified"`) {
+		t.Errorf("update data = %s", got[0].data)
+	}
+
+	got = receive(t, ancient, 2)
+	if want := "item 1-0,item 2-0"; strings.Join(ids(got), ",") != want {
+		t.Errorf("ancient = %v, want %s", ids(got), want)
+	}
+	if !strings.Contains(got[1].data, `"update_id":"u2"`) || !strings.Contains(got[1].data, `"Stone of Jordan"`) {
+		t.Errorf("item data = %s", got[1].data)
+	}
+}
+
+func TestFeed_resume(t *testing.T) {
+	f := NewFeed(WithReplayCapacity(2))
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+	publish(t, f, storetest.Updates()...)
+
+	tests := []struct {
+		name        string
+		url         string
+		lastEventID string
+		want        string
+	}{
+		{name: "after an update", url: srv.URL, lastEventID: "2", want: "update 3"},
+		{name: "evicted update", url: srv.URL, lastEventID: "1", want: "update 2,update 3"},
+		{name: "unknown ID", url: srv.URL, lastEventID: "42", want: "update 2,update 3"},
+		{name: "after an item", url: srv.URL + "?mode=items", lastEventID: "2-0", want: "item 3-0"},
+		{name: "parameter", url: srv.URL + "?last_event_id=2", want: "update 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			events := connect(ctx, t, tt.url, tt.lastEventID)
+			n := strings.Count(tt.want, ",") + 1
+			if got := ids(receive(t, events, n)); strings.Join(got, ",") != tt.want {
+				t.Errorf("replayed %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFeed_heartbeat(t *testing.T) {
+	srv := httptest.NewServer(NewFeed(WithHeartbeat(10 * time.Millisecond)))
+	defer srv.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	select {
+	case ev := <-connect(ctx, t, srv.URL, ""):
+		if ev.comment != "heartbeat" {
+			t.Errorf("event = %+v, want a heartbeat", ev)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no heartbeat")
+	}
+}
+
+func TestFeed_slowClient(t *testing.T) {
+	f := NewFeed()
+	ch, _, _ := f.subscribe(eventID{})
+	for i := 0; i <= subscriberBuffer; i++ {
+		publish(t, f, &rbc.ServerUpdate{})
+	}
+	if f.subscriberCount() != 0 {
+		t.Errorf("the lagging client was not disconnected")
+	}
+	// The queued updates are still delivered, then the channel is closed.
+	n := 0
+	for range ch {
+		n++
+	}
+	if n != subscriberBuffer {
+		t.Errorf("%d updates were queued, want %d", n, subscriberBuffer)
+	}
+}
+
+func TestFeed_badRequest(t *testing.T) {
+	for _, target := range []string{"/?mode=all", "/?rarity=legendary", "/?filter=name"} {
+		rec := httptest.NewRecorder()
+		NewFeed().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("GET %s = %d, want 400", target, rec.Code)
+		}
+	}
+}
+
+func Test_parseEventID(t *testing.T) {
+	tests := []struct {
+		s    string
+		want eventID
+	}{
+		{s: "", want: eventID{}},
+		{s: "12", want: eventID{seq: 12}},
+		{s: "12-3", want: eventID{seq: 12, index: 3, item: true}},
+		{s: "abc", want: eventID{}},
+		{s: "12-x", want: eventID{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			if got := parseEventID(tt.s); got != tt.want {
+				t.Errorf("parseEventID() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package rosbotcollector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// BotFileKind is the kind of a bot configuration file; its value is the segment of the page
+// listing them, i.e. 'user/{user_id}/pickits'.
+type BotFileKind string
+
+const (
+	BotFileProfile      BotFileKind = "profiles"
+	BotFilePickit       BotFileKind = "pickits"
+	BotFileCustomScript BotFileKind = "custom-scripts"
+)
+
+// BotFile is a bot configuration file of the account: a profile, a pickit or a custom script.
+type BotFile struct {
+	Kind        BotFileKind `json:"kind"`
+	Name        string      `json:"name"`
+	Modified    time.Time   `json:"modified"`
+	RawModified string      `json:"raw_modified"`
+	// URL is the absolute URL of the file's page on the site.
+	URL string `json:"url"`
+	// DownloadURL is the absolute URL of the file's content; empty when the site offers none.
+	DownloadURL string          `json:"download_url,omitempty"`
+	Warnings    []*ParseWarning `json:"warnings,omitempty"`
+}
+
+var (
+	// ErrNoDownload is returned when downloading a file which the site offers no download for.
+	ErrNoDownload = errors.New("file has no download link")
+	// ErrExternalLink is returned when a link points outside of the site, where the session
+	// cookies are not sent.
+	ErrExternalLink = errors.New("link does not point to the site")
+)
+
+func (c *client) Profiles(ctx context.Context) ([]*BotFile, error) {
+	return c.botFiles(ctx, BotFileProfile)
+}
+
+func (c *client) Pickits(ctx context.Context) ([]*BotFile, error) {
+	return c.botFiles(ctx, BotFilePickit)
+}
+
+func (c *client) CustomScripts(ctx context.Context) ([]*BotFile, error) {
+	return c.botFiles(ctx, BotFileCustomScript)
+}
+
+func (c *client) botFiles(ctx context.Context, kind BotFileKind) ([]*BotFile, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	res, err := c.httpService.GetUserPage(string(kind))
+	if err != nil {
+		return nil, err
+	}
+	return parseBotFiles(res.Body, kind, c.location)
+}
+
+func (c *client) Download(ctx context.Context, f *BotFile) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if f.DownloadURL == "" {
+		return nil, fmt.Errorf("%w: %s", ErrNoDownload, f.Name)
+	}
+	path, err := sitePath(f.DownloadURL)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.httpService.GetPage(path)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	return ioutil.ReadAll(res.Body)
+}
+
+func parseBotFiles(body io.ReadCloser, kind BotFileKind, loc *time.Location) ([]*BotFile, error) {
+	doc, err := goquery.NewDocumentFromReader(body)
+	if err != nil {
+		return nil, err
+	}
+	_ = body.Close()
+
+	/*
+		<td><a href="https://www.ros-bot.com/profile/9001">Greater Rifts 90</a></td>
+		<td>03/09/2019 - 21:58</td>
+		<td><a href="https://www.ros-bot.com/system/files/profiles/gr90.xml">gr90.xml</a></td>
+	*/
+	files := make([]*BotFile, 0)
+	eachTableRow(doc.Find("div.view-content table"), func(r *tableRow) {
+		name := r.cell("name", "title")
+		f := &BotFile{
+			Kind:        kind,
+			Name:        cleanText(name.Text()),
+			RawModified: r.text("last modified", "updated", "changed"),
+		}
+		href, _ := name.Find("a").Attr("href")
+		f.URL = absoluteURL(href)
+		href, _ = r.cell("download", "file").Find("a").Attr("href")
+		f.DownloadURL = absoluteURL(href)
+
+		t, err := parseTimestamp(f.RawModified, loc)
+		if err != nil {
+			f.Warnings = append(f.Warnings, &ParseWarning{Field: "modified", Raw: f.RawModified, Message: err.Error()})
+		}
+		f.Modified = t
+		files = append(files, f)
+	})
+	return files, nil
+}
+
+// absoluteURL resolves a link of the site against its base URL.
+func absoluteURL(href string) string {
+	if href == "" {
+		return ""
+	}
+	base, _ := url.Parse(baseURL)
+	u, err := base.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return ""
+	}
+	return u.String()
+}
+
+// sitePath returns the path, and query, of a link of the site.
+func sitePath(link string) (string, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", err
+	}
+	base, _ := url.Parse(baseURL)
+	if u.Host != "" && strings.TrimPrefix(u.Host, "www.") != strings.TrimPrefix(base.Host, "www.") {
+		return "", fmt.Errorf("%w: %s", ErrExternalLink, link)
+	}
+	return u.RequestURI(), nil
+}
@@ -0,0 +1,143 @@
+package rosbotcollector
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_parseBotFiles(t *testing.T) {
+	paris, _ := time.LoadLocation("Europe/Paris")
+	tests := []struct {
+		file string
+		kind BotFileKind
+		want []*BotFile
+	}{
+		{
+			file: "./samples/profiles.html",
+			kind: BotFileProfile,
+			want: []*BotFile{
+				{
+					Kind: BotFileProfile, Name: "Greater Rifts 90",
+					Modified: time.Date(2019, 9, 3, 21, 58, 0, 0, paris), RawModified: "03/09/2019 - 21:58",
+					URL:         "https://www.ros-bot.com/profile/9001",
+					DownloadURL: "https://www.ros-bot.com/system/files/profiles/gr90.xml",
+				},
+				{
+					Kind: BotFileProfile, Name: "Bounties",
+					Modified: time.Date(2019, 8, 28, 7, 15, 0, 0, paris), RawModified: "28/08/2019 - 07:15",
+					URL:         "https://www.ros-bot.com/profile/9002",
+					DownloadURL: "https://www.ros-bot.com/system/files/profiles/bounties.xml",
+				},
+			},
+		},
+		{
+			file: "./samples/pickits.html",
+			kind: BotFilePickit,
+			want: []*BotFile{
+				{
+					Kind: BotFilePickit, Name: "Stash ancients",
+					Modified: time.Date(2019, 9, 1, 12, 0, 0, 0, paris), RawModified: "01/09/2019 - 12:00",
+					URL:         "https://www.ros-bot.com/pickit/311",
+					DownloadURL: "https://www.ros-bot.com/system/files/pickits/stash-ancients.txt",
+				},
+			},
+		},
+		{
+			// Custom scripts have no download link.
+			file: "./samples/custom_scripts.html",
+			kind: BotFileCustomScript,
+			want: []*BotFile{
+				{
+					Kind: BotFileCustomScript, Name: "Auto gamble",
+					Modified: time.Date(2019, 8, 15, 9, 30, 0, 0, paris), RawModified: "15/08/2019 - 09:30",
+					URL: "https://www.ros-bot.com/custom-script/77",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.kind), func(t *testing.T) {
+			file, err := os.Open(tt.file)
+			if err != nil {
+				t.Fatalf("could not open html file")
+			}
+			defer file.Close()
+
+			got, err := parseBotFiles(file, tt.kind, paris)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				for i := range got {
+					t.Logf("got[%d] = %+v", i, got[i])
+				}
+				t.Errorf("parseBotFiles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_sitePath(t *testing.T) {
+	tests := []struct {
+		link    string
+		want    string
+		wantErr error
+	}{
+		{link: "https://www.ros-bot.com/system/files/profiles/gr90.xml", want: "/system/files/profiles/gr90.xml"},
+		{link: "https://ros-bot.com/pickit/311/download?v=2", want: "/pickit/311/download?v=2"},
+		{link: "/system/files/bounties.xml", want: "/system/files/bounties.xml"},
+		{link: "https://example.com/gr90.xml", wantErr: ErrExternalLink},
+	}
+	for _, tt := range tests {
+		t.Run(tt.link, func(t *testing.T) {
+			got, err := sitePath(tt.link)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("sitePath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("sitePath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_botFiles(t *testing.T) {
+	s := &fakeHTTPService{
+		pages: map[string]string{
+			"profiles":       "./samples/profiles.html",
+			"pickits":        "./samples/pickits.html",
+			"custom-scripts": "./samples/custom_scripts.html",
+		},
+		files: map[string]string{"/system/files/pickits/stash-ancients.txt": "[Ancient] && [Stash]"},
+	}
+	c := &client{httpService: s, location: time.UTC}
+	ctx := context.Background()
+
+	profiles, err := c.Profiles(ctx)
+	if err != nil || len(profiles) != 2 {
+		t.Errorf("Profiles() = %v, %v", profiles, err)
+	}
+	scripts, err := c.CustomScripts(ctx)
+	if err != nil || len(scripts) != 1 {
+		t.Fatalf("CustomScripts() = %v, %v", scripts, err)
+	}
+	pickits, err := c.Pickits(ctx)
+	if err != nil || len(pickits) != 1 {
+		t.Fatalf("Pickits() = %v, %v", pickits, err)
+	}
+	if want := []string{"profiles", "custom-scripts", "pickits"}; !reflect.DeepEqual(s.segments, want) {
+		t.Errorf("requested segments = %v, want %v", s.segments, want)
+	}
+
+	content, err := c.Download(ctx, pickits[0])
+	if err != nil || string(content) != "[Ancient] && [Stash]" {
+		t.Errorf("Download() = %q, %v", content, err)
+	}
+	if _, err := c.Download(ctx, scripts[0]); !errors.Is(err, ErrNoDownload) {
+		t.Errorf("Download() error = %v, want %v", err, ErrNoDownload)
+	}
+}
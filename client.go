@@ -1,6 +1,10 @@
 package rosbotcollector
 
-import "context"
+import (
+	"context"
+	"net/http"
+	"time"
+)
 
 type (
 	Client interface {
@@ -10,27 +14,99 @@ type (
 		// ParseWithConfig returns a slice of Ros-Bot server updates based on the provided
 		// parsing configuration.
 		ParseWithConfig(ctx context.Context, config *ParserConfig) ([]*ServerUpdate, error)
+		// ParsePageWithConfig returns a page of Ros-Bot server updates, alongside its pagination
+		// metadata, based on the provided parsing configuration.
+		ParsePageWithConfig(ctx context.Context, config *ParserConfig) (*ActivityPage, error)
+		// Coins returns the coin balance of the account, alongside its latest transactions.
+		Coins(ctx context.Context) (*Coins, error)
+		// Licenses returns the bot licenses of the account, alongside their expiry dates.
+		Licenses(ctx context.Context) ([]*License, error)
+		// Profiles returns the bot profiles of the account.
+		Profiles(ctx context.Context) ([]*BotFile, error)
+		// Pickits returns the pickits of the account.
+		Pickits(ctx context.Context) ([]*BotFile, error)
+		// CustomScripts returns the custom scripts of the account.
+		CustomScripts(ctx context.Context) ([]*BotFile, error)
+		// Download returns the content of a bot file, through the session of the client.
+		Download(ctx context.Context, f *BotFile) ([]byte, error)
+		// DropStatistics returns the global drop statistics of the site.
+		DropStatistics(ctx context.Context) (*DropStatistics, error)
+		// Orders returns a page of the orders of the account, alongside its pagination metadata.
+		Orders(ctx context.Context, page PageNumber) (*OrderPage, error)
 	}
 
 	client struct {
 		httpService HTTPService
+		location    *time.Location
+	}
+
+	// ClientOption configures an optional behaviour of the client.
+	ClientOption func(*clientOptions)
+
+	clientOptions struct {
+		location       *time.Location
+		detectTimezone bool
+		transport      http.RoundTripper
 	}
 )
 
+// WithLocation sets the timezone in which the site renders timestamps.
+// It is used whenever `ParserConfig.Location` is nil.
+func WithLocation(loc *time.Location) ClientOption {
+	return func(o *clientOptions) { o.location = loc }
+}
+
+// WithTimezoneDetection makes the client read the display timezone from the account edit page
+// ('user/{user_id}/edit') upon authentication. It takes precedence over `WithLocation`.
+func WithTimezoneDetection() ClientOption {
+	return func(o *clientOptions) { o.detectTimezone = true }
+}
+
+// WithTransport sets the transport of every request made to the site, i.e. to instrument them.
+// `http.DefaultTransport` is used otherwise.
+func WithTransport(rt http.RoundTripper) ClientOption {
+	return func(o *clientOptions) { o.transport = rt }
+}
+
 // NewClient a instance of the `rosbotcollector.Client` interface.
-func NewClient(usernameOrEmail string, password string) (Client, error) {
-	s, err := newHTTPService(usernameOrEmail, password)
+func NewClient(usernameOrEmail string, password string, opts ...ClientOption) (Client, error) {
+	o := &clientOptions{location: time.UTC}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	s, err := newHTTPService(usernameOrEmail, password, o.transport)
 	if err != nil {
 		return nil, err
 	}
-	return &client{httpService: s}, nil
+
+	if o.detectTimezone {
+		res, err := s.GetUserPage("edit")
+		if err != nil {
+			return nil, err
+		}
+		if o.location, err = parseTimezone(res.Body); err != nil {
+			return nil, err
+		}
+	}
+	return &client{httpService: s, location: o.location}, nil
 }
 
 func (c *client) ParseWithDefaults(ctx context.Context) ([]*ServerUpdate, error) {
 	config := NewParseConfig()
-	return newParser(config, c.httpService).Parse(ctx)
+	return newParser(config, c.httpService, c.location).Parse(ctx)
 }
 
 func (c *client) ParseWithConfig(ctx context.Context, config *ParserConfig) ([]*ServerUpdate, error) {
-	return newParser(config, c.httpService).Parse(ctx)
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return newParser(config, c.httpService, c.location).Parse(ctx)
+}
+
+func (c *client) ParsePageWithConfig(ctx context.Context, config *ParserConfig) (*ActivityPage, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return newParser(config, c.httpService, c.location).ParsePage(ctx)
 }
@@ -1,7 +1,13 @@
 package rosbotcollector
 
 import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -41,3 +47,60 @@ func TestNewClient(t *testing.T) {
 		})
 	}
 }
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestWithTransport(t *testing.T) {
+	errOffline := errors.New("offline")
+	var urls []string
+	rt := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		urls = append(urls, req.URL.String())
+		return nil, errOffline
+	})
+
+	if _, err := NewClient("test", "test", WithTransport(rt)); !errors.Is(err, errOffline) {
+		t.Errorf("NewClient() error = %v, want %v", err, errOffline)
+	}
+	if want := []string{baseURL + loginEndpoint}; !reflect.DeepEqual(urls, want) {
+		t.Errorf("transport requests = %v, want %v", urls, want)
+	}
+}
+
+// fakeHTTPService serves the sample files of `pages`, keyed by user page segment, and the
+// content of `files` and `public`, keyed by path.
+type fakeHTTPService struct {
+	HTTPService
+	pages    map[string]string
+	files    map[string]string
+	public   map[string]string
+	segments []string
+}
+
+func (s *fakeHTTPService) GetUserPage(segment string) (*http.Response, error) {
+	s.segments = append(s.segments, segment)
+	f, err := os.Open(s.pages[segment])
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: f}, nil
+}
+
+func (s *fakeHTTPService) GetPage(path string) (*http.Response, error) {
+	return serveContent(s.files, path)
+}
+
+func (s *fakeHTTPService) GetPublicPage(path string) (*http.Response, error) {
+	return serveContent(s.public, path)
+}
+
+func serveContent(contents map[string]string, path string) (*http.Response, error) {
+	content, ok := contents[path]
+	if !ok {
+		return nil, fmt.Errorf("%w: 404 Not Found", ErrUnexpectedStatus)
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(content))}, nil
+}
@@ -0,0 +1,640 @@
+// Command rosbot-collector scrapes Ros-Bot's user activity page from the command line.
+//
+//	rosbot-collector <command> [flags]
+//
+// Run rosbot-collector without arguments for the list of commands.
+//
+// The ndjson and ndjson-items formats stream a line per server update, or per item, as pages are
+// parsed.
+//
+// Credentials are read from the ROSBOT_USERNAME and ROSBOT_PASSWORD environment variables, or from
+// a JSON file ({"username": "...", "password": "..."}) given by -credentials.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	rbc "github.com/maxzaleski/go-rosbot-collector"
+	"github.com/maxzaleski/go-rosbot-collector/internal/cli"
+	"github.com/maxzaleski/go-rosbot-collector/stats"
+)
+
+// commands are the subcommands, in the order of the usage.
+var commands = []struct {
+	name    string
+	summary string
+	run     func(context.Context, *options, []string) error
+}{
+	{"login", "checks the credentials", login},
+	{"fetch", "prints a single page of server updates", fetch},
+	{"crawl", "prints several pages of server updates", crawl},
+	{"watch", "polls the activity page, and prints new server updates as they are collected", watch},
+	{"export", "writes several pages of server updates to a file", export},
+	{"stats", "prints a summary of several pages of server updates", printStats},
+	{"coins", "prints the coin balance, and how long it lasts at the current spending rate", printCoins},
+	{"licenses", "prints the bot licenses, and the days left before they expire", printLicenses},
+	{"backup", "downloads the bot profiles, pickits and custom scripts to a directory", backup},
+	{"compare", "contrasts the drop rates of several pages of server updates with the global ones", compare},
+	{"orders", "prints the order history", printOrders},
+}
+
+// writeUsage prints the usage of the command, i.e. the list of subcommands.
+func writeUsage(w io.Writer) {
+	fmt.Fprint(w, "usage: rosbot-collector <command> [flags]\n\ncommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-9s %s\n", c.name, c.summary)
+	}
+	fmt.Fprint(w, "\nRun 'rosbot-collector <command> -h' for the flags of a command.\n")
+}
+
+// errUsage is returned when the command line is invalid; the usage has already been printed.
+var errUsage = errors.New("invalid usage")
+
+// newClient is replaced in tests.
+var newClient = rbc.NewClient
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		cancel()
+	}()
+
+	if err := run(ctx, os.Args[1:], os.Stdout, os.Stderr, os.Getenv); err != nil {
+		if !errors.Is(err, errUsage) {
+			fmt.Fprintln(os.Stderr, "rosbot-collector:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer, getenv func(string) string) error {
+	if len(args) == 0 {
+		writeUsage(stderr)
+		return errUsage
+	}
+
+	var cmd func(context.Context, *options, []string) error
+	for _, c := range commands {
+		if c.name == args[0] {
+			cmd = c.run
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(stderr, "unknown command %q\n\n", args[0])
+		writeUsage(stderr)
+		return errUsage
+	}
+
+	o := &options{stdout: stdout, stderr: stderr, getenv: getenv}
+	fs := o.flagSet(args[0])
+	if err := fs.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return errUsage
+	}
+	return cmd(ctx, o, fs.Args())
+}
+
+// options are the flags shared by every command.
+type options struct {
+	stdout, stderr io.Writer
+	getenv         func(string) string
+
+	account      cli.Account
+	destinations string
+	rarity       string
+	quality      string
+	filter       rbc.Query
+	format       string
+	columns      string
+	noHeader     bool
+	page         int
+	pages        int
+	delay        time.Duration
+	interval     time.Duration
+	groupBy      string
+	output       string
+	window       time.Duration
+	within       time.Duration
+	all          bool
+
+	// c is the authenticated client, shared by the steps of a command.
+	c rbc.Client
+}
+
+func (o *options) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(o.stderr)
+
+	o.account.RegisterFlags(fs)
+	switch name {
+	case "login":
+		return fs
+	case "coins":
+		fs.StringVar(&o.format, "format", "table", "output format: table, json")
+		fs.DurationVar(&o.window, "window", 7*24*time.Hour, "period over which the spending rate is measured")
+		return fs
+	case "licenses":
+		fs.StringVar(&o.format, "format", "table", "output format: table, json")
+		fs.DurationVar(&o.within, "within", 0, "only print the licenses expiring within this duration, and fail if any")
+		return fs
+	case "backup":
+		fs.StringVar(&o.output, "o", "", "output directory; required")
+		return fs
+	case "orders":
+		fs.StringVar(&o.format, "format", "table", "output format: table, json, csv")
+		fs.IntVar(&o.page, "page", 1, "one-based page number")
+		fs.BoolVar(&o.all, "all", false, "print the orders of every page")
+		fs.BoolVar(&o.noHeader, "no-header", false, "omit the CSV header")
+		return fs
+	case "compare":
+		// The global statistics cover every drop; filters would skew the account's shares.
+		fs.StringVar(&o.format, "format", "table", "output format: table, json")
+		fs.IntVar(&o.page, "page", 1, "one-based number of the first page")
+		fs.IntVar(&o.pages, "pages", 1, "number of pages; 0 crawls until the last page")
+		fs.DurationVar(&o.delay, "delay", time.Second, "delay between two pages")
+		return fs
+	}
+
+	fs.StringVar(&o.destinations, "destinations", "", "comma-separated destinations: stashed, salvaged, sold")
+	fs.StringVar(&o.rarity, "rarity", "non-ancient", "minimum rarity: non-ancient, ancient, primal")
+	fs.StringVar(&o.quality, "quality", "all", "quality: all, normal, set")
+	fs.Var(&o.filter, "filter", `filter query, i.e. 'rarity>=ancient and name~"tyrael"'`)
+	fs.StringVar(&o.format, "format", "table", "output format: table, json, csv, ndjson, ndjson-items")
+	fs.StringVar(&o.columns, "columns", "", "comma-separated CSV columns; defaults to timestamp,bot,name,rarity,quality,destination,identified,stats")
+	fs.BoolVar(&o.noHeader, "no-header", false, "omit the CSV header")
+
+	switch name {
+	case "fetch":
+		fs.IntVar(&o.page, "page", 1, "one-based page number")
+	case "crawl", "export", "stats":
+		fs.IntVar(&o.page, "page", 1, "one-based number of the first page")
+		fs.IntVar(&o.pages, "pages", 1, "number of pages; 0 crawls until the last page")
+		fs.DurationVar(&o.delay, "delay", time.Second, "delay between two pages")
+		if name == "stats" {
+			fs.StringVar(&o.groupBy, "group-by", "", "comma-separated dimensions of a breakdown: rarity, quality, destination, name, bot")
+		}
+	case "watch":
+		fs.DurationVar(&o.interval, "interval", 5*time.Minute, "polling interval")
+	}
+	if name == "export" {
+		fs.StringVar(&o.output, "o", "", "output file; required")
+	}
+	return fs
+}
+
+// parserConfig maps the flags onto a parsing configuration.
+func (o *options) parserConfig() (*rbc.ParserConfig, error) {
+	c := rbc.NewParseConfig().WithPage(rbc.PageNumber(o.page))
+	if o.page == 0 {
+		c.WithPage(rbc.FirstPage)
+	}
+
+	if o.destinations != "" {
+		for _, d := range strings.Split(o.destinations, ",") {
+			c.Destinations = append(c.Destinations, rbc.Destination(strings.ToUpper(strings.TrimSpace(d))))
+		}
+	}
+
+	switch strings.ToLower(o.rarity) {
+	case "non-ancient", "nonancient", "":
+		c.MinRarity(rbc.RarityNonAncient)
+	default:
+		c.MinRarity(rbc.Rarity(strings.ToUpper(o.rarity)))
+	}
+
+	switch strings.ToLower(o.quality) {
+	case "all", "*", "":
+		c.WithQuality(rbc.QualityAll)
+	case "legendary":
+		c.WithQuality(rbc.QualityNormal)
+	default:
+		c.WithQuality(rbc.Quality(strings.ToUpper(o.quality)))
+	}
+
+	c.WithFilter(o.filter.Predicate())
+	return c, c.Validate()
+}
+
+// client authenticates with the configured credentials, once per command.
+func (o *options) client() (rbc.Client, error) {
+	if o.c != nil {
+		return o.c, nil
+	}
+	creds, err := o.account.LoadCredentials(o.getenv)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := o.account.ClientOptions()
+	if err != nil {
+		return nil, err
+	}
+	c, err := newClient(creds.Username, creds.Password, opts...)
+	if err != nil {
+		return nil, err
+	}
+	o.c = c
+	return c, nil
+}
+
+func login(_ context.Context, o *options, _ []string) error {
+	if _, err := o.client(); err != nil {
+		return err
+	}
+	fmt.Fprintln(o.stdout, "authenticated")
+	return nil
+}
+
+func fetch(ctx context.Context, o *options, _ []string) error {
+	config, err := o.parserConfig()
+	if err != nil {
+		return err
+	}
+	c, err := o.client()
+	if err != nil {
+		return err
+	}
+	updates, err := c.ParseWithConfig(ctx, config)
+	if err != nil {
+		return err
+	}
+	return o.writeUpdates(o.stdout, updates)
+}
+
+func crawl(ctx context.Context, o *options, _ []string) error {
+	uw, err := o.newWriter(o.stdout)
+	if err != nil {
+		return err
+	}
+	_, err = crawlTo(ctx, o, uw)
+	return err
+}
+
+func export(ctx context.Context, o *options, _ []string) error {
+	if o.output == "" {
+		return errors.New("-o is required")
+	}
+	f, err := os.Create(o.output)
+	if err != nil {
+		return err
+	}
+	uw, err := o.newWriter(f)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	n, err := crawlTo(ctx, o, uw)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.stderr, "exported %d server updates to %s\n", n, o.output)
+	return nil
+}
+
+func printStats(ctx context.Context, o *options, _ []string) error {
+	groupBy, err := stats.ParseDimensions(o.groupBy)
+	if err != nil {
+		return err
+	}
+	updates, err := crawlPages(ctx, o, nil)
+	if err != nil {
+		return err
+	}
+	if err := rbc.BuildDigest(updates, time.Time{}, time.Time{}).RenderText(o.stdout, nil); err != nil {
+		return err
+	}
+	if len(groupBy) == 0 {
+		return nil
+	}
+
+	r, err := stats.Aggregate(updates, &stats.Config{GroupBy: groupBy})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(o.stdout)
+	return writeGroups(o.stdout, r)
+}
+
+func printCoins(ctx context.Context, o *options, _ []string) error {
+	c, err := o.client()
+	if err != nil {
+		return err
+	}
+	coins, err := c.Coins(ctx)
+	if err != nil {
+		return err
+	}
+	switch o.format {
+	case "json":
+		enc := json.NewEncoder(o.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(coins)
+	case "table", "":
+		return writeCoins(o.stdout, coins, time.Now(), o.window)
+	default:
+		return fmt.Errorf("unknown format %q", o.format)
+	}
+}
+
+func printLicenses(ctx context.Context, o *options, _ []string) error {
+	c, err := o.client()
+	if err != nil {
+		return err
+	}
+	licenses, err := c.Licenses(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if o.within > 0 {
+		licenses = rbc.ExpiringLicenses(licenses, now, o.within)
+	}
+
+	switch o.format {
+	case "json":
+		enc := json.NewEncoder(o.stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(licenses)
+	case "table", "":
+		err = writeLicenses(o.stdout, licenses, now)
+	default:
+		return fmt.Errorf("unknown format %q", o.format)
+	}
+	if err != nil {
+		return err
+	}
+	// A non-zero exit status is what cron jobs alert on.
+	if o.within > 0 && len(licenses) > 0 {
+		return fmt.Errorf("%d license(s) expiring within %s, or whose expiry date is unknown", len(licenses), o.within)
+	}
+	return nil
+}
+
+func compare(ctx context.Context, o *options, _ []string) error {
+	c, err := o.client()
+	if err != nil {
+		return err
+	}
+	global, err := c.DropStatistics(ctx)
+	if err != nil {
+		return err
+	}
+	updates, err := crawlPages(ctx, o, nil)
+	if err != nil {
+		return err
+	}
+	cmp, err := rbc.CompareDropRates(updates, global)
+	if err != nil {
+		return err
+	}
+	if o.format == "json" {
+		enc := json.NewEncoder(o.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(cmp)
+	}
+	return writeComparison(o.stdout, cmp)
+}
+
+func printOrders(ctx context.Context, o *options, _ []string) error {
+	c, err := o.client()
+	if err != nil {
+		return err
+	}
+	var orders []*rbc.Order
+	if o.all {
+		orders, err = rbc.AllOrders(ctx, c)
+	} else {
+		var page *rbc.OrderPage
+		if page, err = c.Orders(ctx, rbc.PageNumber(o.page)); err == nil {
+			orders = page.Orders
+			fmt.Fprintf(o.stderr, "page %d/%d: %d orders\n", page.Info.CurrentPage, page.Info.TotalPages, len(orders))
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	switch o.format {
+	case "json":
+		enc := json.NewEncoder(o.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(orders)
+	case "csv":
+		return writeOrdersCSV(o.stdout, orders, !o.noHeader)
+	case "table", "":
+		return writeOrders(o.stdout, orders)
+	default:
+		return fmt.Errorf("unknown format %q", o.format)
+	}
+}
+
+// backup writes the content of every downloadable bot file to '{-o}/{kind}/{file name}', and the
+// listing of every file, downloadable or not, to '{-o}/index.json'.
+func backup(ctx context.Context, o *options, _ []string) error {
+	if o.output == "" {
+		return errors.New("-o is required")
+	}
+	c, err := o.client()
+	if err != nil {
+		return err
+	}
+
+	files := make([]*rbc.BotFile, 0)
+	for _, list := range []func(context.Context) ([]*rbc.BotFile, error){c.Profiles, c.Pickits, c.CustomScripts} {
+		listed, err := list(ctx)
+		if err != nil {
+			return err
+		}
+		files = append(files, listed...)
+	}
+
+	written := 0
+	// Precaution; a file must not overwrite another one of the same backup.
+	backedUp := make(map[string]*rbc.BotFile)
+	for _, f := range files {
+		if f.DownloadURL == "" {
+			continue
+		}
+		dir := filepath.Join(o.output, string(f.Kind))
+		name := filepath.Join(dir, backupFileName(f))
+		if other, ok := backedUp[name]; ok {
+			return fmt.Errorf("%s %q: %s is already the backup of %q", f.Kind, f.Name, name, other.Name)
+		}
+		backedUp[name] = f
+
+		content, err := c.Download(ctx, f)
+		if err != nil {
+			return fmt.Errorf("%s %q: %v", f.Kind, f.Name, err)
+		}
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(name, content, 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintln(o.stdout, name)
+		written++
+	}
+
+	index, err := json.MarshalIndent(files, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(o.output, 0o755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(o.output, "index.json"), append(index, '\n'), 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.stderr, "backed up %d of %d bot files to %s\n", written, len(files), o.output)
+	return nil
+}
+
+// backupFileName is the name of the downloaded file, or of the bot file when the download URL
+// does not end with one, prefixed with the node ID of the file's page, i.e. '9001-gr90.xml', since
+// several files may share a name.
+func backupFileName(f *rbc.BotFile) string {
+	name := lastSegment(f.DownloadURL)
+	if name == "" {
+		name = f.Name
+	}
+	if id := lastSegment(f.URL); id != "" {
+		name = id + "-" + name
+	}
+	// Names come from the site, and must not escape the directory.
+	return strings.NewReplacer("/", "_", "\\", "_", "..", "_").Replace(name)
+}
+
+// lastSegment returns the last segment of the link's path; empty when there is none.
+func lastSegment(link string) string {
+	u, err := url.Parse(link)
+	if err != nil || link == "" {
+		return ""
+	}
+	name := path.Base(u.Path)
+	if name == "." || name == "/" || name == ".." {
+		return ""
+	}
+	return name
+}
+
+// crawlTo writes the crawled server updates, and returns their number. Streaming formats are
+// written page by page, as they are parsed; the others once the crawl is over, oldest first.
+func crawlTo(ctx context.Context, o *options, uw *updateWriter) (int, error) {
+	if !uw.streams() {
+		updates, err := crawlPages(ctx, o, nil)
+		if err != nil {
+			return 0, err
+		}
+		return len(updates), uw.write(updates)
+	}
+
+	n := 0
+	_, err := crawlPages(ctx, o, func(page []*rbc.ServerUpdate) error {
+		n += len(page)
+		return uw.write(page)
+	})
+	return n, err
+}
+
+// crawlPages parses `-pages` pages from `-page`. Pages are passed to `onPage` as they are parsed,
+// if not nil; otherwise every server update is returned, oldest first.
+func crawlPages(ctx context.Context, o *options, onPage func([]*rbc.ServerUpdate) error) ([]*rbc.ServerUpdate, error) {
+	config, err := o.parserConfig()
+	if err != nil {
+		return nil, err
+	}
+	c, err := o.client()
+	if err != nil {
+		return nil, err
+	}
+
+	var updates []*rbc.ServerUpdate
+	for i := 0; o.pages == 0 || i < o.pages; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(o.delay):
+			}
+		}
+
+		page, err := c.ParsePageWithConfig(ctx, config)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(o.stderr, "page %d/%d: %d server updates\n", page.Info.CurrentPage, page.Info.TotalPages, len(page.Updates))
+		if onPage != nil {
+			if err := onPage(page.Updates); err != nil {
+				return nil, err
+			}
+		} else {
+			// Pages are ordered newest first, their updates oldest first.
+			updates = append(page.Updates, updates...)
+		}
+
+		next, ok := page.Info.NextPage()
+		if !ok {
+			break
+		}
+		config.WithPage(next)
+	}
+	return updates, nil
+}
+
+func watch(ctx context.Context, o *options, _ []string) error {
+	config, err := o.parserConfig()
+	if err != nil {
+		return err
+	}
+	c, err := o.client()
+	if err != nil {
+		return err
+	}
+
+	cc := rbc.NewCollectorConfig()
+	cc.Parser = config
+	cc.Interval = o.interval
+	cc.OnError = func(err error) { fmt.Fprintln(o.stderr, "rosbot-collector:", err) }
+
+	uw, err := o.newWriter(o.stdout)
+	if err != nil {
+		return err
+	}
+	collector := rbc.NewCollector(c, cc)
+	collector.Handle(func(_ context.Context, u *rbc.ServerUpdate) error {
+		if len(u.Items) == 0 {
+			return nil
+		}
+		return uw.write([]*rbc.ServerUpdate{u})
+	})
+
+	// Interrupting is the expected way of stopping.
+	if err := collector.Run(ctx); err != context.Canceled {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,467 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	rbc "github.com/maxzaleski/go-rosbot-collector"
+)
+
+// fakeClient serves `pages` through `ParsePageWithConfig`, and the first page otherwise.
+type fakeClient struct {
+	pages    [][]*rbc.ServerUpdate
+	configs  []*rbc.ParserConfig
+	coins    *rbc.Coins
+	licenses []*rbc.License
+	files    []*rbc.BotFile
+	global   *rbc.DropStatistics
+	orders   [][]*rbc.Order
+}
+
+func (c *fakeClient) ParseWithDefaults(ctx context.Context) ([]*rbc.ServerUpdate, error) {
+	return c.ParseWithConfig(ctx, rbc.NewParseConfig())
+}
+
+func (c *fakeClient) ParseWithConfig(ctx context.Context, config *rbc.ParserConfig) ([]*rbc.ServerUpdate, error) {
+	page, err := c.ParsePageWithConfig(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return page.Updates, nil
+}
+
+func (c *fakeClient) ParsePageWithConfig(_ context.Context, config *rbc.ParserConfig) (*rbc.ActivityPage, error) {
+	copied := *config
+	c.configs = append(c.configs, &copied)
+	i, err := config.PageIndex()
+	if err != nil {
+		return nil, err
+	}
+	return &rbc.ActivityPage{
+		Updates: c.pages[i],
+		Info: &rbc.PageInfo{
+			CurrentPage: rbc.PageFromIndex(i),
+			TotalPages:  len(c.pages),
+			HasNext:     i < len(c.pages)-1,
+			HasPrevious: i > 0,
+		},
+	}, nil
+}
+
+func (c *fakeClient) Coins(context.Context) (*rbc.Coins, error) {
+	return c.coins, nil
+}
+
+func (c *fakeClient) Licenses(context.Context) ([]*rbc.License, error) {
+	return c.licenses, nil
+}
+
+func (c *fakeClient) Profiles(context.Context) ([]*rbc.BotFile, error) {
+	return c.botFiles(rbc.BotFileProfile), nil
+}
+
+func (c *fakeClient) Pickits(context.Context) ([]*rbc.BotFile, error) {
+	return c.botFiles(rbc.BotFilePickit), nil
+}
+
+func (c *fakeClient) CustomScripts(context.Context) ([]*rbc.BotFile, error) {
+	return c.botFiles(rbc.BotFileCustomScript), nil
+}
+
+func (c *fakeClient) botFiles(kind rbc.BotFileKind) []*rbc.BotFile {
+	var files []*rbc.BotFile
+	for _, f := range c.files {
+		if f.Kind == kind {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+func (c *fakeClient) Download(_ context.Context, f *rbc.BotFile) ([]byte, error) {
+	return []byte("content of " + f.Name), nil
+}
+
+func (c *fakeClient) DropStatistics(context.Context) (*rbc.DropStatistics, error) {
+	return c.global, nil
+}
+
+func (c *fakeClient) Orders(_ context.Context, page rbc.PageNumber) (*rbc.OrderPage, error) {
+	i := page.Index()
+	if i < 0 || i >= len(c.orders) {
+		return nil, rbc.ErrInvalidPage
+	}
+	return &rbc.OrderPage{
+		Orders: c.orders[i],
+		Info:   &rbc.PageInfo{CurrentPage: page, TotalPages: len(c.orders), HasNext: i < len(c.orders)-1},
+	}, nil
+}
+
+func testPages() [][]*rbc.ServerUpdate {
+	ts := time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)
+	update := func(id, name string, at time.Time) *rbc.ServerUpdate {
+		return &rbc.ServerUpdate{
+			ID:              id,
+			ServerTimestamp: at,
+			Items: []*rbc.LegendaryItem{{
+				ID: id + "-0", Name: name, Quality: rbc.QualityNormal, Rarity: rbc.RarityAncient,
+				Destination: rbc.DestinationStashed, IsIdentified: true, BotName: "bot1", Stats: "+10 Strength",
+			}},
+		}
+	}
+	return [][]*rbc.ServerUpdate{
+		{update("c", "Tyrael's Might", ts.Add(2*time.Hour))},
+		{update("a", "Furnace", ts), update("b", "Stone of Jordan", ts.Add(time.Hour))},
+	}
+}
+
+func testCoins() *rbc.Coins {
+	now := time.Now()
+	return &rbc.Coins{
+		Balance: 90,
+		Pending: 10,
+		Transactions: []*rbc.CoinTransaction{
+			{Amount: -30, Date: now.Add(-time.Hour), Reason: "Bot license: 1 day", Status: "Approved"},
+			{Amount: -30, Date: now.Add(-25 * time.Hour), Reason: "Bot license: 1 day", Status: "Approved"},
+		},
+	}
+}
+
+func testLicenses() []*rbc.License {
+	now := time.Now()
+	return []*rbc.License{
+		{Key: "***-****-****-M8LA", Type: "30 days", Status: "Active", Expires: now.Add(36 * time.Hour)},
+		{Key: "***-****-****-P0WE", Type: "Lifetime", Status: "Active", NeverExpires: true, RawExpires: "Never"},
+	}
+}
+
+func testDropStatistics() *rbc.DropStatistics {
+	return &rbc.DropStatistics{
+		Total:    100,
+		Rarities: []*rbc.RarityDrops{{Rarity: rbc.RarityNonAncient, Drops: 90, Share: 0.9}, {Rarity: rbc.RarityAncient, Drops: 10, Share: 0.1}},
+		Items:    []*rbc.ItemDrops{{Name: "Furnace", Drops: 4, Share: 0.04}},
+	}
+}
+
+func testOrders() [][]*rbc.Order {
+	return [][]*rbc.Order{
+		{{ID: "4521", Date: time.Date(2019, 9, 5, 10, 12, 0, 0, time.UTC), Product: "1,000 coins", Amount: 999, Currency: "€", RawAmount: "€9.99", Status: "Pending"}},
+		{
+			{ID: "3977", RawDate: "yesterday", Product: "Bot license: 1 day", Amount: 200, Currency: "USD", RawAmount: "USD 2.00", Status: "Canceled"},
+			{ID: "3976", RawDate: "yesterday", Product: "Refund", Amount: -5, Currency: "USD", RawAmount: "-USD 0.05", Status: "Completed"},
+		},
+	}
+}
+
+func runWithClient(t *testing.T, c rbc.Client, args ...string) (string, string, error) {
+	t.Helper()
+	newClient = func(username, password string, _ ...rbc.ClientOption) (rbc.Client, error) {
+		if username != "user" || password != "pass" {
+			return nil, rbc.ErrBadCredentials
+		}
+		return c, nil
+	}
+	defer func() { newClient = rbc.NewClient }()
+
+	env := map[string]string{"ROSBOT_USERNAME": "user", "ROSBOT_PASSWORD": "pass"}
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), args, &stdout, &stderr, func(k string) string { return env[k] })
+	return stdout.String(), stderr.String(), err
+}
+
+func Test_run(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		// licenses default to `testLicenses`.
+		licenses []*rbc.License
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:    "no command",
+			wantErr: true,
+		},
+		{
+			name:    "unknown command",
+			args:    []string{"scrape"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown flag",
+			args:    []string{"fetch", "-pages", "2"},
+			wantErr: true,
+		},
+		{
+			name: "login",
+			args: []string{"login"},
+			want: []string{"authenticated"},
+		},
+		{
+			name: "fetch table",
+			args: []string{"fetch"},
+			want: []string{"TIMESTAMP", "Tyrael's Might", "ANCIENT", "STASHED"},
+		},
+		{
+			name: "crawl csv",
+			args: []string{"crawl", "-pages", "0", "-delay", "0", "-format", "csv"},
+			want: []string{"timestamp,bot,name", "2020-05-01T12:00:00Z,bot1,Furnace,ANCIENT,NORMAL,STASHED,true,+10 Strength"},
+		},
+		{
+			name: "crawl csv columns",
+			args: []string{"crawl", "-format", "csv", "-columns", "item_id,name", "-no-header"},
+			want: []string{"c-0,Tyrael's Might\n"},
+		},
+		{
+			name:    "crawl csv invalid column",
+			args:    []string{"crawl", "-format", "csv", "-columns", "colour"},
+			wantErr: true,
+		},
+		{
+			name: "crawl json",
+			args: []string{"crawl", "-format", "json"},
+			want: []string{`"legendaries"`, `"Tyrael's Might"`},
+		},
+		{
+			name: "crawl ndjson",
+			args: []string{"crawl", "-pages", "0", "-delay", "0", "-format", "ndjson"},
+			want: []string{"{\"id\":\"c\",", "\n{\"id\":\"a\","},
+		},
+		{
+			name: "crawl ndjson items",
+			args: []string{"crawl", "-format", "ndjson-items"},
+			want: []string{"{\"update_id\":\"c\",\"server_timestamp\":\"2020-05-01T14:00:00Z\",\"id\":\"c-0\","},
+		},
+		{
+			name: "stats",
+			args: []string{"stats", "-pages", "2", "-delay", "0"},
+			want: []string{"3 legendary items in 3 server updates"},
+		},
+		{
+			name: "stats group by",
+			args: []string{"stats", "-pages", "2", "-delay", "0", "-group-by", "bot,rarity"},
+			want: []string{"BOT   RARITY   COUNT  SHARE   PER HOUR  MEDIAN INTERVAL", "bot1  ANCIENT  3      100.0%  1.50      1h0m0s"},
+		},
+		{
+			// A single update spans no time; its rate is not computed.
+			name: "stats group by single update",
+			args: []string{"stats", "-pages", "1", "-delay", "0", "-group-by", "rarity"},
+			want: []string{"ANCIENT  1      100.0%  -         0s"},
+		},
+		{
+			name:    "stats invalid group by",
+			args:    []string{"stats", "-group-by", "colour"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid quality",
+			args:    []string{"fetch", "-quality", "rare"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid filter",
+			args:    []string{"fetch", "-filter", "rarity >="},
+			wantErr: true,
+		},
+		{
+			name: "coins",
+			args: []string{"coins", "-window", "48h"},
+			want: []string{"balance: 90 coins (10 pending)", "3.0 days left", "-30    Bot license: 1 day  Approved"},
+		},
+		{
+			name: "coins json",
+			args: []string{"coins", "-format", "json"},
+			want: []string{`"balance": 90`},
+		},
+		{
+			name: "licenses",
+			args: []string{"licenses"},
+			want: []string{"KEY                 TYPE", "***-****-****-M8LA  30 days   Active  ", "  1\n", "***-****-****-P0WE  Lifetime  Active  Never"},
+		},
+		{
+			name:     "licenses unknown expiry",
+			args:     []string{"licenses", "-within", "12h"},
+			licenses: []*rbc.License{{Key: "***-****-****-Q7RS", Type: "7 days", Status: "Pending", RawExpires: "12 Oct 2019"}},
+			want:     []string{"***-****-****-Q7RS  7 days  Pending  12 Oct 2019  ?"},
+			wantErr:  true,
+		},
+		{
+			name:    "licenses expiring",
+			args:    []string{"licenses", "-within", "72h"},
+			want:    []string{"M8LA"},
+			wantErr: true,
+		},
+		{
+			name: "licenses not expiring",
+			args: []string{"licenses", "-within", "12h", "-format", "json"},
+			want: []string{"[]"},
+		},
+		{
+			name: "compare",
+			args: []string{"compare", "-pages", "0", "-delay", "0"},
+			want: []string{"3 legendary items, against 100 on the site", "ANCIENT      3      100.00%  10.00%      10.00", "Furnace          1      33.33%  4.00%       8.33", "Tyrael's Might   1      33.33%  0.00%       -\n"},
+		},
+		{
+			name:    "compare filtered",
+			args:    []string{"compare", "-rarity", "ancient"},
+			wantErr: true,
+		},
+		{
+			name: "orders",
+			args: []string{"orders"},
+			want: []string{"ID    DATE              PRODUCT      AMOUNT  STATUS", "4521  2019-09-05 10:12  1,000 coins  €9.99   Pending"},
+		},
+		{
+			name: "orders csv",
+			args: []string{"orders", "-all", "-format", "csv"},
+			want: []string{"id,date,product,amount,currency,status\n4521,2019-09-05 10:12,\"1,000 coins\",9.99,€,Pending\n3977,yesterday,Bot license: 1 day,2.00,USD,Canceled\n3976,yesterday,Refund,-0.05,USD,Completed\n"},
+		},
+		{
+			name:    "orders invalid page",
+			args:    []string{"orders", "-page", "3"},
+			wantErr: true,
+		},
+		{
+			name:    "coins unknown flag",
+			args:    []string{"coins", "-rarity", "primal"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			licenses := tt.licenses
+			if licenses == nil {
+				licenses = testLicenses()
+			}
+			got, _, err := runWithClient(t, &fakeClient{pages: testPages(), coins: testCoins(), licenses: licenses, global: testDropStatistics(), orders: testOrders()}, tt.args...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("run() output = %q, want it to contain %q", got, w)
+				}
+			}
+		})
+	}
+}
+
+func Test_writeUsage(t *testing.T) {
+	var b strings.Builder
+	writeUsage(&b)
+	for _, c := range commands {
+		if !strings.Contains(b.String(), "  "+c.name+" ") {
+			t.Errorf("writeUsage() = %q, want it to list %q", b.String(), c.name)
+		}
+	}
+}
+
+func Test_crawlPages_order(t *testing.T) {
+	got, _, err := runWithClient(t, &fakeClient{pages: testPages()}, "crawl", "-pages", "0", "-delay", "0", "-format", "csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Oldest first, across pages.
+	a, b, c := strings.Index(got, "Furnace"), strings.Index(got, "Stone of Jordan"), strings.Index(got, "Tyrael")
+	if !(a < b && b < c) {
+		t.Errorf("crawl output is not ordered oldest first:\n%s", got)
+	}
+}
+
+func Test_options_parserConfig(t *testing.T) {
+	c := &fakeClient{pages: testPages()}
+	_, _, err := runWithClient(t, c, "fetch", "-page", "2", "-destinations", "stashed, sold",
+		"-rarity", "primal", "-quality", "set", "-filter", `name~"jordan"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := c.configs[0]
+	if got.PageNumber != 2 {
+		t.Errorf("PageNumber = %v, want 2", got.PageNumber)
+	}
+	if len(got.Destinations) != 2 || got.Destinations[0] != rbc.DestinationStashed || got.Destinations[1] != rbc.DestinationSold {
+		t.Errorf("Destinations = %v", got.Destinations)
+	}
+	if got.RarityLevel != rbc.RarityPrimal {
+		t.Errorf("RarityLevel = %v, want %v", got.RarityLevel, rbc.RarityPrimal)
+	}
+	if got.Quality != rbc.QualitySet {
+		t.Errorf("Quality = %v, want %v", got.Quality, rbc.QualitySet)
+	}
+	if got.Filter == nil {
+		t.Error("Filter is nil")
+	}
+}
+
+func Test_options_client(t *testing.T) {
+	o := &options{getenv: func(string) string { return "" }}
+	if _, err := o.client(); err == nil || !strings.Contains(err.Error(), "missing credentials") {
+		t.Errorf("client() error = %v, want missing credentials", err)
+	}
+
+	_, _, err := runWithClient(t, &fakeClient{}, "login", "-timezone", "Mars/Olympus_Mons")
+	if err == nil {
+		t.Error("client() accepted an unknown timezone")
+	}
+	if errors.Is(err, errUsage) {
+		t.Errorf("client() error = %v, want a timezone error", err)
+	}
+}
+
+func Test_backup(t *testing.T) {
+	dir := t.TempDir()
+	c := &fakeClient{files: []*rbc.BotFile{
+		{Kind: rbc.BotFileProfile, Name: "Greater Rifts 90", URL: "https://www.ros-bot.com/profile/9001", DownloadURL: "https://www.ros-bot.com/system/files/profiles/gr90.xml"},
+		// Same file name, another profile.
+		{Kind: rbc.BotFileProfile, Name: "Greater Rifts 90 (solo)", URL: "https://www.ros-bot.com/profile/9003", DownloadURL: "https://www.ros-bot.com/system/files/profiles/solo/gr90.xml"},
+		{Kind: rbc.BotFilePickit, Name: "Stash ancients", DownloadURL: "https://www.ros-bot.com/pickit/311/.."},
+		{Kind: rbc.BotFileCustomScript, Name: "Auto gamble"},
+	}}
+	stdout, stderr, err := runWithClient(t, c, "backup", "-o", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(stderr, "backed up 3 of 4 bot files") {
+		t.Errorf("backup stderr = %q", stderr)
+	}
+
+	for name, want := range map[string]string{
+		filepath.Join(dir, "profiles", "9001-gr90.xml"): "content of Greater Rifts 90",
+		filepath.Join(dir, "profiles", "9003-gr90.xml"): "content of Greater Rifts 90 (solo)",
+		filepath.Join(dir, "pickits", "Stash ancients"): "content of Stash ancients",
+	} {
+		if !strings.Contains(stdout, name) {
+			t.Errorf("backup stdout = %q, want it to contain %q", stdout, name)
+		}
+		if got, err := ioutil.ReadFile(name); err != nil || string(got) != want {
+			t.Errorf("%s = %q, %v, want %q", name, got, err, want)
+		}
+	}
+
+	var index []*rbc.BotFile
+	b, _ := ioutil.ReadFile(filepath.Join(dir, "index.json"))
+	if err := json.Unmarshal(b, &index); err != nil || len(index) != 4 || index[3].Name != "Auto gamble" {
+		t.Errorf("index.json = %s, %v", b, err)
+	}
+
+	// Without a page to tell them apart, files of the same name are not overwritten.
+	c = &fakeClient{files: []*rbc.BotFile{
+		{Kind: rbc.BotFileProfile, Name: "Greater Rifts 90", DownloadURL: "https://www.ros-bot.com/system/files/profiles/gr90.xml"},
+		{Kind: rbc.BotFileProfile, Name: "Greater Rifts 90 (solo)", DownloadURL: "https://www.ros-bot.com/system/files/profiles/solo/gr90.xml"},
+	}}
+	if _, _, err := runWithClient(t, c, "backup", "-o", t.TempDir()); err == nil || !strings.Contains(err.Error(), "already the backup of") {
+		t.Errorf("backup error = %v, want a collision", err)
+	}
+
+	if _, _, err := runWithClient(t, c, "backup"); err == nil {
+		t.Error("backup accepted a missing -o")
+	}
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	rbc "github.com/maxzaleski/go-rosbot-collector"
+	"github.com/maxzaleski/go-rosbot-collector/stats"
+)
+
+// updateWriter writes server updates in the format given by `-format`; successive calls to
+// `write` produce a single document where the format allows it, i.e. a single CSV header.
+type updateWriter struct {
+	format string
+	w      io.Writer
+	csv    *rbc.CSVWriter
+	ndjson *rbc.NDJSONEncoder
+}
+
+func (o *options) newWriter(w io.Writer) (*updateWriter, error) {
+	uw := &updateWriter{format: o.format, w: w}
+	switch o.format {
+	case "table", "", "json":
+	case "csv":
+		uw.csv = rbc.NewCSVWriter(w)
+		uw.csv.NoHeader = o.noHeader
+		if o.columns != "" {
+			columns, err := rbc.ParseCSVColumns(o.columns)
+			if err != nil {
+				return nil, err
+			}
+			uw.csv.Columns = columns
+		}
+	case "ndjson", "ndjson-items":
+		uw.ndjson = rbc.NewNDJSONEncoder(w)
+		uw.ndjson.Flatten = o.format == "ndjson-items"
+	default:
+		return nil, fmt.Errorf("unknown format %q", o.format)
+	}
+	return uw, nil
+}
+
+// streams reports whether the format allows writing pages as they are parsed.
+func (uw *updateWriter) streams() bool {
+	return uw.ndjson != nil
+}
+
+func (uw *updateWriter) write(updates []*rbc.ServerUpdate) error {
+	switch uw.format {
+	case "json":
+		enc := json.NewEncoder(uw.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(updates)
+	case "ndjson", "ndjson-items":
+		return uw.ndjson.EncodeAll(updates)
+	case "csv":
+		if err := uw.csv.WriteRecords(rbc.FlattenUpdates(updates)); err != nil {
+			return err
+		}
+		return uw.csv.Flush()
+	default:
+		return writeTable(uw.w, updates)
+	}
+}
+
+// writeUpdates writes the server updates to w, in the format given by `-format`.
+func (o *options) writeUpdates(w io.Writer, updates []*rbc.ServerUpdate) error {
+	uw, err := o.newWriter(w)
+	if err != nil {
+		return err
+	}
+	return uw.write(updates)
+}
+
+func writeTable(w io.Writer, updates []*rbc.ServerUpdate) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIMESTAMP\tBOT\tNAME\tTYPE\tRARITY\tDESTINATION")
+	for _, u := range updates {
+		for _, item := range u.Items {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", u.ServerTimestamp.Format("2006-01-02 15:04"),
+				item.BotName, item.Name, item.Quality, item.Rarity, item.Destination)
+		}
+	}
+	return tw.Flush()
+}
+
+// writeGroups writes the breakdown of a report, one group per row. Rates are '-' over periods
+// shorter than the resolution of server timestamps.
+func writeGroups(w io.Writer, r *stats.Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := make([]string, len(r.GroupBy))
+	for i, d := range r.GroupBy {
+		header[i] = strings.ToUpper(string(d))
+	}
+	fmt.Fprintf(tw, "%s\tCOUNT\tSHARE\tPER HOUR\tMEDIAN INTERVAL\n", strings.Join(header, "\t"))
+	for _, g := range r.Groups {
+		share := float64(g.Count) / float64(r.Total)
+		perHour := "-"
+		if d := r.Duration(); d >= stats.Resolution {
+			perHour = fmt.Sprintf("%.2f", float64(g.Count)*float64(time.Hour)/float64(d))
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%.1f%%\t%s\t%s\n", strings.Join(g.Key, "\t"), g.Count, share*100, perHour,
+			g.Intervals.Percentile(50).Round(time.Second))
+	}
+	return tw.Flush()
+}
+
+// writeCoins writes the balance, the estimated days left over `window`, and the transactions.
+func writeCoins(w io.Writer, coins *rbc.Coins, now time.Time, window time.Duration) error {
+	fmt.Fprintf(w, "balance: %d coins (%d pending)\n", coins.Balance, coins.Pending)
+	if days, ok := coins.DaysLeft(now, window); ok {
+		fmt.Fprintf(w, "%.1f days left at the spending rate of the last %s\n", days, window)
+	} else {
+		fmt.Fprintf(w, "no coins spent in the last %s\n", window)
+	}
+	fmt.Fprintln(w)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "DATE\tCOINS\tREASON\tSTATUS")
+	for _, t := range coins.Transactions {
+		date := t.RawDate
+		if !t.Date.IsZero() {
+			date = t.Date.Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(tw, "%s\t%+d\t%s\t%s\n", date, t.Amount, t.Reason, t.Status)
+	}
+	return tw.Flush()
+}
+
+// writeLicenses writes the licenses, one per row; licenses which never expire have no days left,
+// and those whose expiry date is unknown have '?'.
+func writeLicenses(w io.Writer, licenses []*rbc.License, now time.Time) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tTYPE\tSTATUS\tEXPIRES\tDAYS LEFT")
+	for _, l := range licenses {
+		expires, left := l.RawExpires, "-"
+		days, err := l.DaysUntilExpiry(now)
+		switch {
+		case err == nil:
+			expires, left = l.Expires.Format("2006-01-02 15:04"), strconv.Itoa(days)
+		case errors.Is(err, rbc.ErrUnknownExpiry):
+			left = "?"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", l.Key, l.Type, l.Status, expires, left)
+	}
+	return tw.Flush()
+}
+
+// writeComparison writes the rarities, then the items, of a drop rate comparison.
+func writeComparison(w io.Writer, c *rbc.DropComparison) error {
+	fmt.Fprintf(w, "%d legendary items, against %d on the site\n", c.Total, c.GlobalTotal)
+	for _, section := range []struct {
+		header string
+		rows   []*rbc.DropRateComparison
+	}{{"RARITY", c.Rarities}, {"ITEM", c.Items}} {
+		fmt.Fprintln(w)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "%s\tDROPS\tSHARE\tSITE SHARE\tRATIO\n", section.header)
+		for _, r := range section.rows {
+			ratio := "-"
+			if r.GlobalShare > 0 {
+				ratio = fmt.Sprintf("%.2f", r.Ratio)
+			}
+			fmt.Fprintf(tw, "%s\t%d\t%.2f%%\t%.2f%%\t%s\n", r.Key, r.Drops, r.Share*100, r.GlobalShare*100, ratio)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeOrders(w io.Writer, orders []*rbc.Order) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tDATE\tPRODUCT\tAMOUNT\tSTATUS")
+	for _, order := range orders {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", order.ID, orderDate(order), order.Product, order.RawAmount, order.Status)
+	}
+	return tw.Flush()
+}
+
+// writeOrdersCSV writes an order per record, with the amount in units of the currency, for
+// spreadsheets and bookkeeping tools.
+func writeOrdersCSV(w io.Writer, orders []*rbc.Order, header bool) error {
+	cw := csv.NewWriter(w)
+	if header {
+		_ = cw.Write([]string{"id", "date", "product", "amount", "currency", "status"})
+	}
+	for _, order := range orders {
+		sign, cents := "", order.Amount
+		if cents < 0 {
+			sign, cents = "-", -cents
+		}
+		amount := fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+		_ = cw.Write([]string{order.ID, orderDate(order), order.Product, amount, order.Currency, order.Status})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// orderDate is the date of the order, or its raw text when it could not be parsed.
+func orderDate(order *rbc.Order) string {
+	if order.Date.IsZero() {
+		return order.RawDate
+	}
+	return order.Date.Format("2006-01-02 15:04")
+}
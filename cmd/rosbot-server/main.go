@@ -0,0 +1,256 @@
+// Command rosbot-server polls Ros-Bot's user activity page in the background, and serves the
+// collected server updates over an HTTP JSON API, so that dashboards do not each need Ros-Bot
+// credentials.
+//
+//	rosbot-server [-addr :8080] [-db drops.db] [-interval 5m]
+//
+// Endpoints:
+//
+//	GET /updates   server updates, filtered by since, until, rarity, quality, destination, name,
+//	               bot and filter; paged by limit and offset
+//	GET /items/ID  a single item
+//	GET /stats     item counts, bucketed by bucket and grouped by group_by
+//	GET /events    Server-Sent Events of the newly collected server updates, filtered as
+//	               /updates; resumed after Last-Event-ID
+//	GET /healthz   whether the collector polls successfully
+//	GET /metrics   Prometheus metrics
+//
+// With -grpc-addr, the client is also served over gRPC; see the rpc package.
+//
+// Server updates are kept in memory unless -db is set, in which case they are saved to a SQLite
+// database.
+//
+// Credentials are read from the ROSBOT_USERNAME and ROSBOT_PASSWORD environment variables, or from
+// a JSON file ({"username": "...", "password": "..."}) given by -credentials.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+
+	rbc "github.com/maxzaleski/go-rosbot-collector"
+	"github.com/maxzaleski/go-rosbot-collector/api"
+	"github.com/maxzaleski/go-rosbot-collector/internal/cli"
+	"github.com/maxzaleski/go-rosbot-collector/metrics"
+	"github.com/maxzaleski/go-rosbot-collector/rpc"
+	"github.com/maxzaleski/go-rosbot-collector/rpc/rosbotpb"
+	"github.com/maxzaleski/go-rosbot-collector/store"
+	"github.com/maxzaleski/go-rosbot-collector/store/sqlite"
+)
+
+// newClient is replaced in tests.
+var newClient = rbc.NewClient
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		cancel()
+	}()
+
+	if err := run(ctx, os.Args[1:], os.Stderr, os.Getenv); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "rosbot-server:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+type options struct {
+	stderr io.Writer
+	getenv func(string) string
+
+	addr     string
+	grpcAddr string
+	db       string
+	account  cli.Account
+	interval time.Duration
+	maxAge   time.Duration
+}
+
+func run(ctx context.Context, args []string, stderr io.Writer, getenv func(string) string) error {
+	o := &options{stderr: stderr, getenv: getenv}
+	fs := flag.NewFlagSet("rosbot-server", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&o.addr, "addr", ":8080", "listen address")
+	fs.StringVar(&o.grpcAddr, "grpc-addr", "", "gRPC listen address; gRPC is not served otherwise")
+	fs.StringVar(&o.db, "db", "", "SQLite database file; server updates are kept in memory otherwise")
+	o.account.RegisterFlags(fs)
+	fs.DurationVar(&o.interval, "interval", 5*time.Minute, "polling interval")
+	fs.DurationVar(&o.maxAge, "max-age", 0, "age of the last successful poll after which /healthz fails; defaults to 3 intervals")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if o.maxAge <= 0 {
+		o.maxAge = 3 * o.interval
+	}
+
+	s, err := o.store(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	exporter := metrics.NewExporter()
+	c, err := o.client(rbc.WithTransport(exporter.RoundTripper(nil)))
+	if err != nil {
+		return err
+	}
+	c = exporter.Client(c)
+	srv := newServer(c, s, o)
+	srv.collector.Handle(exporter.Observe)
+	srv.mux.Handle("/metrics", exporter.Handler())
+
+	hs := &http.Server{
+		Addr:              o.addr,
+		Handler:           srv.mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		// Live feeds are closed on shutdown, rather than waited for.
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	errs := make(chan error, 3)
+	go func() { errs <- srv.collector.Run(ctx) }()
+	go func() { errs <- hs.ListenAndServe() }()
+	fmt.Fprintf(o.stderr, "rosbot-server: listening on %s\n", o.addr)
+
+	if o.grpcAddr != "" {
+		lis, err := net.Listen("tcp", o.grpcAddr)
+		if err != nil {
+			_ = hs.Close()
+			return err
+		}
+		gs := grpc.NewServer()
+		rosbotpb.RegisterCollectorServiceServer(gs, rpc.NewServer(c, rpc.WithErrorHandler(srv.reportError)))
+		go func() { errs <- gs.Serve(lis) }()
+		defer gs.Stop()
+		fmt.Fprintf(o.stderr, "rosbot-server: serving gRPC on %s\n", o.grpcAddr)
+	}
+
+	select {
+	case <-ctx.Done():
+	case err := <-errs:
+		_ = hs.Close()
+		return err
+	}
+	shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return hs.Shutdown(shutdown)
+}
+
+// server holds the collector, and the handlers serving what it collects.
+type server struct {
+	collector *rbc.Collector
+	health    *health
+	mux       *http.ServeMux
+	stderr    io.Writer
+}
+
+func newServer(c rbc.Client, s store.Store, o *options) *server {
+	h := newHealth(c, o.maxAge, time.Now)
+
+	cc := rbc.NewCollectorConfig()
+	cc.Interval = o.interval
+	srv := &server{health: h, mux: http.NewServeMux(), stderr: o.stderr}
+	cc.OnError = srv.reportError
+	collector := rbc.NewCollector(h, cc)
+	collector.Handle(store.Handler(s))
+	feed := api.NewFeed()
+	collector.Handle(feed.Publish)
+
+	srv.collector = collector
+	srv.mux.Handle("/", api.NewHandler(s, api.WithHealthCheck(h.check)))
+	srv.mux.Handle("/events", feed)
+	return srv
+}
+
+func (s *server) reportError(err error) {
+	fmt.Fprintln(s.stderr, "rosbot-server:", err)
+}
+
+// health records the outcome of the polls of the client.
+type health struct {
+	rbc.Client
+	maxAge time.Duration
+	now    func() time.Time
+
+	mu          sync.Mutex
+	started     time.Time
+	lastSuccess time.Time
+	lastErr     error
+}
+
+// newHealth starts the clock: a collector that never completes a poll is unhealthy after `maxAge`.
+func newHealth(c rbc.Client, maxAge time.Duration, now func() time.Time) *health {
+	return &health{Client: c, maxAge: maxAge, now: now, started: now()}
+}
+
+func (h *health) ParseWithConfig(ctx context.Context, config *rbc.ParserConfig) ([]*rbc.ServerUpdate, error) {
+	updates, err := h.Client.ParseWithConfig(ctx, config)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err != nil {
+		h.lastErr = err
+	} else {
+		h.lastSuccess, h.lastErr = h.now(), nil
+	}
+	return updates, err
+}
+
+// check fails when no poll succeeded within `maxAge`, whether the polls fail or hang; isolated
+// failures are tolerated.
+func (h *health) check(context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	since := h.lastSuccess
+	if since.IsZero() {
+		since = h.started
+	}
+	if h.now().Sub(since) < h.maxAge {
+		return nil
+	}
+	switch {
+	case h.lastErr == nil && h.lastSuccess.IsZero():
+		return fmt.Errorf("no poll completed since %s", h.started.Format(time.RFC3339))
+	case h.lastErr == nil:
+		return fmt.Errorf("no poll completed since %s", h.lastSuccess.Format(time.RFC3339))
+	case h.lastSuccess.IsZero():
+		return fmt.Errorf("no successful poll: %v", h.lastErr)
+	}
+	return fmt.Errorf("no successful poll since %s: %v", h.lastSuccess.Format(time.RFC3339), h.lastErr)
+}
+
+func (o *options) store(ctx context.Context) (store.Store, error) {
+	if o.db == "" {
+		return store.NewMemory(), nil
+	}
+	return sqlite.Open(ctx, o.db)
+}
+
+// client authenticates with the configured credentials.
+func (o *options) client(opts ...rbc.ClientOption) (rbc.Client, error) {
+	creds, err := o.account.LoadCredentials(o.getenv)
+	if err != nil {
+		return nil, err
+	}
+	tz, err := o.account.ClientOptions()
+	if err != nil {
+		return nil, err
+	}
+	return newClient(creds.Username, creds.Password, append(opts, tz...)...)
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	rbc "github.com/maxzaleski/go-rosbot-collector"
+	"github.com/maxzaleski/go-rosbot-collector/store"
+	"github.com/maxzaleski/go-rosbot-collector/store/storetest"
+)
+
+type fakeClient struct {
+	rbc.Client
+	updates []*rbc.ServerUpdate
+	err     error
+}
+
+func (c *fakeClient) ParseWithConfig(context.Context, *rbc.ParserConfig) ([]*rbc.ServerUpdate, error) {
+	return c.updates, c.err
+}
+
+func Test_newServer(t *testing.T) {
+	ctx := context.Background()
+	var stderr bytes.Buffer
+	c := &fakeClient{updates: storetest.Updates()}
+	srv := newServer(c, store.NewMemory(), &options{stderr: &stderr, interval: time.Minute, maxAge: time.Hour})
+
+	if _, err := srv.collector.Collect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	srv.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/updates?rarity=ancient", nil))
+	var updates []*rbc.ServerUpdate
+	if err := json.Unmarshal(rec.Body.Bytes(), &updates); err != nil {
+		t.Fatalf("invalid response %q: %v", rec.Body.String(), err)
+	}
+	if len(updates) != 2 || updates[0].ID != "u1" || updates[1].ID != "u2" {
+		t.Errorf("GET /updates = %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	srv.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("GET /healthz = %d %s, want 200", rec.Code, rec.Body.String())
+	}
+
+	// The feed validates its parameters before streaming.
+	rec = httptest.NewRecorder()
+	srv.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events?mode=all", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("GET /events = %d %s, want 400", rec.Code, rec.Body.String())
+	}
+}
+
+func Test_health_check(t *testing.T) {
+	t0 := time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		// polls are the outcomes of the polls, one minute apart.
+		polls []error
+		// idle is the time since the last poll.
+		idle    time.Duration
+		wantErr string
+	}{
+		{name: "not polled", polls: nil},
+		{name: "first poll hung", polls: nil, idle: 3 * time.Minute, wantErr: "no poll completed since 2020-05-01T12:00:00Z"},
+		{name: "poll hung", polls: []error{nil}, idle: 3 * time.Minute, wantErr: "no poll completed since 2020-05-01T12:00:00Z"},
+		{name: "successful", polls: []error{nil, nil}},
+		{name: "isolated failure", polls: []error{nil, errors.New("timeout")}},
+		{name: "failing since", polls: []error{nil, errors.New("timeout"), errors.New("timeout"), errors.New("timeout")}, wantErr: "no successful poll since 2020-05-01T12:00:00Z: timeout"},
+		{name: "never successful", polls: []error{errors.New("bad credentials"), errors.New("bad credentials"), errors.New("bad credentials"), errors.New("bad credentials")}, wantErr: "no successful poll: bad credentials"},
+		{name: "recovered", polls: []error{errors.New("timeout"), errors.New("timeout"), errors.New("timeout"), nil}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := t0
+			c := &fakeClient{}
+			h := newHealth(c, 3*time.Minute, func() time.Time { return now })
+			for _, err := range tt.polls {
+				c.err = err
+				_, _ = h.ParseWithConfig(context.Background(), nil)
+				now = now.Add(time.Minute)
+			}
+			now = now.Add(tt.idle)
+
+			err := h.check(context.Background())
+			if got := ""; err != nil {
+				got = err.Error()
+				if got != tt.wantErr {
+					t.Errorf("check() error = %q, want %q", got, tt.wantErr)
+				}
+			} else if tt.wantErr != "" {
+				t.Errorf("check() error = nil, want %q", tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_run_missingCredentials(t *testing.T) {
+	var stderr bytes.Buffer
+	getenv := func(string) string { return "" }
+	err := run(context.Background(), []string{"-addr", "127.0.0.1:0"}, &stderr, getenv)
+	if err == nil || !strings.Contains(err.Error(), "missing credentials") {
+		t.Errorf("run() error = %v, want missing credentials", err)
+	}
+}
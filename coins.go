@@ -0,0 +1,130 @@
+package rosbotcollector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Coins is the coin balance of the account, alongside its latest transactions, as displayed by
+// the 'user/{user_id}/points' page.
+type Coins struct {
+	// Balance is the approved balance; coins awaiting moderation cannot be spent yet.
+	Balance      int                `json:"balance"`
+	Pending      int                `json:"pending"`
+	Transactions []*CoinTransaction `json:"transactions"`
+}
+
+// CoinTransaction is a single coin transaction, i.e. an order or a license renewal.
+type CoinTransaction struct {
+	// Amount is negative when coins were spent.
+	Amount   int             `json:"amount"`
+	Date     time.Time       `json:"date"`
+	RawDate  string          `json:"raw_date"`
+	Reason   string          `json:"reason"`
+	Status   string          `json:"status"`
+	Warnings []*ParseWarning `json:"warnings,omitempty"`
+}
+
+// ErrNoCoinBalance is returned when the coin balance could not be parsed from response body.
+var ErrNoCoinBalance = errors.New("could not parse coin balance from response body")
+
+func (c *client) Coins(ctx context.Context) (*Coins, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	res, err := c.httpService.GetUserPage("points")
+	if err != nil {
+		return nil, err
+	}
+	return parseCoins(res.Body, c.location)
+}
+
+func parseCoins(body io.ReadCloser, loc *time.Location) (*Coins, error) {
+	doc, err := goquery.NewDocumentFromReader(body)
+	if err != nil {
+		return nil, err
+	}
+	_ = body.Close()
+
+	/*
+		<tr><th>Approved coins balance</th><td>1,250</td></tr>
+		<tr><th>Coins awaiting moderation</th><td>100</td></tr>
+	*/
+	coins := &Coins{Transactions: make([]*CoinTransaction, 0)}
+	found := false
+	doc.Find("table.userpoints-summary tr").Each(func(_ int, s *goquery.Selection) {
+		label := strings.ToLower(s.Find("th").Text())
+		amount, err := parseCoinAmount(s.Find("td").Text())
+		if err != nil {
+			return
+		}
+		switch {
+		case strings.Contains(label, "approved"):
+			coins.Balance, found = amount, true
+		case strings.Contains(label, "awaiting"):
+			coins.Pending = amount
+		}
+	})
+	// Precaution.
+	if !found {
+		return nil, ErrNoCoinBalance
+	}
+
+	eachTableRow(doc.Find("table.userpoints-transactions"), func(r *tableRow) {
+		t := &CoinTransaction{
+			RawDate: r.text("date"),
+			Reason:  r.text("reason"),
+			Status:  r.text("status"),
+		}
+		raw := r.text("coins", "points")
+		var err error
+		if t.Amount, err = parseCoinAmount(raw); err != nil {
+			t.Warnings = append(t.Warnings, &ParseWarning{Field: "amount", Raw: raw, Message: err.Error()})
+		}
+		if t.Date, err = parseTimestamp(t.RawDate, loc); err != nil {
+			t.Warnings = append(t.Warnings, &ParseWarning{Field: "date", Raw: t.RawDate, Message: err.Error()})
+		}
+		coins.Transactions = append(coins.Transactions, t)
+	})
+	return coins, nil
+}
+
+// parseCoinAmount parses a signed amount of coins, i.e. "+1,000" or "-30".
+func parseCoinAmount(raw string) (int, error) {
+	s := strings.NewReplacer(",", "", " ", "", "+", "").Replace(strings.TrimSpace(raw))
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount of coins %q", raw)
+	}
+	return n, nil
+}
+
+// Spent returns the amount of coins spent since the given time, by the dated transactions.
+// Only the latest transactions are listed by the site, which bounds how far back it may look.
+func (c *Coins) Spent(since time.Time) int {
+	spent := 0
+	for _, t := range c.Transactions {
+		if t.Amount < 0 && !t.Date.IsZero() && !t.Date.Before(since) {
+			spent -= t.Amount
+		}
+	}
+	return spent
+}
+
+// DaysLeft estimates how many days the balance lasts at the rate coins were spent over the
+// `window` preceding `now`. It is false when no coins were spent over the window.
+func (c *Coins) DaysLeft(now time.Time, window time.Duration) (float64, bool) {
+	spent := c.Spent(now.Add(-window))
+	if spent <= 0 || window <= 0 {
+		return 0, false
+	}
+	perDay := float64(spent) / (window.Hours() / 24)
+	return float64(c.Balance) / perDay, true
+}
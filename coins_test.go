@@ -0,0 +1,139 @@
+package rosbotcollector
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"math"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_parseCoins(t *testing.T) {
+	file, err := os.Open("./samples/points.html")
+	if err != nil {
+		t.Fatalf("could not open html file")
+	}
+	defer file.Close()
+
+	paris, _ := time.LoadLocation("Europe/Paris")
+	got, err := parseCoins(file, paris)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Balance != 1250 || got.Pending != 100 {
+		t.Errorf("parseCoins() balance = %d, pending = %d, want 1250, 100", got.Balance, got.Pending)
+	}
+
+	var amounts []int
+	for _, tr := range got.Transactions {
+		amounts = append(amounts, tr.Amount)
+	}
+	if want := []int{100, -30, -30, 1000, -60}; !reflect.DeepEqual(amounts, want) {
+		t.Errorf("parseCoins() amounts = %v, want %v", amounts, want)
+	}
+	first := got.Transactions[0]
+	if want := time.Date(2019, 9, 5, 10, 12, 0, 0, paris); !first.Date.Equal(want) || first.Reason != "Order #4521" || first.Status != "Pending" {
+		t.Errorf("parseCoins() first transaction = %+v", first)
+	}
+	// Unparsable dates are not fatal.
+	last := got.Transactions[4]
+	if !last.Date.IsZero() || len(last.Warnings) != 1 || last.Warnings[0].Field != "date" || last.Warnings[0].Raw != "yesterday" {
+		t.Errorf("parseCoins() last transaction = %+v, want a date warning", last)
+	}
+}
+
+func Test_parseCoins_noBalance(t *testing.T) {
+	body := ioutil.NopCloser(strings.NewReader("<html><body>Access denied</body></html>"))
+	if _, err := parseCoins(body, time.UTC); !errors.Is(err, ErrNoCoinBalance) {
+		t.Errorf("parseCoins() error = %v, want %v", err, ErrNoCoinBalance)
+	}
+}
+
+func Test_parseCoinAmount(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    int
+		wantErr bool
+	}{
+		{raw: "30", want: 30},
+		{raw: " -30 ", want: -30},
+		{raw: "+1,000", want: 1000},
+		{raw: "1 250", want: 1250},
+		{raw: "", wantErr: true},
+		{raw: "thirty", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := parseCoinAmount(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCoinAmount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseCoinAmount() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCoins_DaysLeft(t *testing.T) {
+	now := time.Date(2019, 9, 10, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	coins := &Coins{
+		Balance: 90,
+		Transactions: []*CoinTransaction{
+			{Amount: -30, Date: now.Add(-1 * day)},
+			{Amount: -30, Date: now.Add(-2 * day)},
+			{Amount: 1000, Date: now.Add(-3 * day)},
+			{Amount: -60, Date: now.Add(-10 * day)},
+			// Undated transactions are ignored.
+			{Amount: -500},
+		},
+	}
+	tests := []struct {
+		name   string
+		window time.Duration
+		want   float64
+		wantOK bool
+	}{
+		{name: "last week", window: 7 * day, want: 10.5, wantOK: true},
+		{name: "last fortnight", window: 14 * day, want: 10.5, wantOK: true},
+		{name: "nothing spent", window: 12 * time.Hour, wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := coins.DaysLeft(now, tt.window)
+			if ok != tt.wantOK || math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("DaysLeft() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+	if got := coins.Spent(now.Add(-7 * day)); got != 60 {
+		t.Errorf("Spent() = %d, want 60", got)
+	}
+}
+
+func TestClient_Coins(t *testing.T) {
+	s := &fakeHTTPService{pages: map[string]string{"points": "./samples/points.html"}}
+	c := &client{httpService: s, location: time.UTC}
+
+	got, err := c.Coins(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Balance != 1250 || len(got.Transactions) != 5 {
+		t.Errorf("Coins() = %+v", got)
+	}
+	if want := []string{"points"}; !reflect.DeepEqual(s.segments, want) {
+		t.Errorf("requested segments = %v, want %v", s.segments, want)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.Coins(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Coins() error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,200 @@
+package rosbotcollector
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+type (
+	// UpdateHandler handles a server update newly collected by a `Collector`.
+	// Returned errors are reported to `CollectorConfig.OnError`; they do not stop the collector.
+	UpdateHandler func(ctx context.Context, u *ServerUpdate) error
+
+	// CollectorConfig is the polling configuration of a `Collector`.
+	CollectorConfig struct {
+		// Parser is the parsing configuration of every poll; defaults to `NewParseConfig()`.
+		Parser *ParserConfig
+		// Interval is the delay between two successful polls.
+		Interval time.Duration
+		// Jitter is the maximum random delay added to, or removed from, the interval.
+		Jitter time.Duration
+		// MinBackoff is the delay following a first failed poll; it doubles on every subsequent
+		// failure, up to MaxBackoff.
+		MinBackoff time.Duration
+		MaxBackoff time.Duration
+		// SkipExisting marks the updates of the first poll as seen without handling them.
+		SkipExisting bool
+		// SeenCapacity is the number of update IDs remembered.
+		SeenCapacity int
+		// OnError is called with every failed poll, and handler error.
+		OnError func(err error)
+	}
+
+	// Collector polls the '/bot-activity' page, and delivers new server updates to its handlers.
+	Collector struct {
+		client   Client
+		config   CollectorConfig
+		rand     *rand.Rand
+		mu       sync.Mutex
+		handlers []UpdateHandler
+		seen     map[string]struct{}
+		// order is the insertion order of `seen`, oldest first.
+		order []string
+		polls int
+	}
+)
+
+// NewCollectorConfig returns a new instance of `rosbotcollector.CollectorConfig` with the default
+// values.
+func NewCollectorConfig() *CollectorConfig {
+	return &CollectorConfig{
+		Parser:       NewParseConfig(),
+		Interval:     5 * time.Minute,
+		Jitter:       30 * time.Second,
+		MinBackoff:   10 * time.Second,
+		MaxBackoff:   10 * time.Minute,
+		SeenCapacity: 10000,
+	}
+}
+
+// NewCollector returns a new instance of `rosbotcollector.Collector`.
+// Zero values of the configuration are replaced by the defaults, but for `Jitter`.
+func NewCollector(c Client, config *CollectorConfig) *Collector {
+	conf := *NewCollectorConfig()
+	if config != nil {
+		if config.Parser != nil {
+			conf.Parser = config.Parser
+		}
+		if config.Interval > 0 {
+			conf.Interval = config.Interval
+		}
+		conf.Jitter = config.Jitter
+		if config.MinBackoff > 0 {
+			conf.MinBackoff = config.MinBackoff
+		}
+		if config.MaxBackoff > 0 {
+			conf.MaxBackoff = config.MaxBackoff
+		}
+		if config.SeenCapacity > 0 {
+			conf.SeenCapacity = config.SeenCapacity
+		}
+		conf.SkipExisting = config.SkipExisting
+		conf.OnError = config.OnError
+	}
+
+	return &Collector{
+		client: c,
+		config: conf,
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+		seen:   make(map[string]struct{}),
+	}
+}
+
+// Handle registers a handler of new server updates.
+func (c *Collector) Handle(h UpdateHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers = append(c.handlers, h)
+}
+
+// Run polls until the context is cancelled, which is the only returned error.
+func (c *Collector) Run(ctx context.Context) error {
+	failures := 0
+	for {
+		delay := c.nextInterval()
+		if _, err := c.Collect(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			c.report(err)
+			delay = c.backoff(failures)
+			failures++
+		} else {
+			failures = 0
+		}
+
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// Collect polls once, and delivers the server updates which were not seen before to the handlers,
+// oldest first. The new updates are returned.
+func (c *Collector) Collect(ctx context.Context) ([]*ServerUpdate, error) {
+	updates, err := c.client.ParseWithConfig(ctx, c.config.Parser)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	first := c.polls == 0
+	c.polls++
+	fresh := make([]*ServerUpdate, 0, len(updates))
+	for _, u := range updates {
+		if _, ok := c.seen[u.ID]; ok {
+			continue
+		}
+		c.markSeen(u.ID)
+		fresh = append(fresh, u)
+	}
+	handlers := append([]UpdateHandler{}, c.handlers...)
+	c.mu.Unlock()
+
+	if first && c.config.SkipExisting {
+		return nil, nil
+	}
+	for _, u := range fresh {
+		for _, h := range handlers {
+			if err := h(ctx, u); err != nil {
+				c.report(err)
+			}
+		}
+	}
+	return fresh, nil
+}
+
+// markSeen must be called with the lock held.
+func (c *Collector) markSeen(id string) {
+	c.seen[id] = struct{}{}
+	c.order = append(c.order, id)
+	// The oldest IDs are forgotten first; they are the least likely to be displayed again.
+	for len(c.order) > c.config.SeenCapacity {
+		delete(c.seen, c.order[0])
+		c.order = c.order[1:]
+	}
+}
+
+func (c *Collector) nextInterval() time.Duration {
+	if c.config.Jitter <= 0 {
+		return c.config.Interval
+	}
+	d := c.config.Interval + time.Duration(c.rand.Int63n(int64(2*c.config.Jitter+1))) - c.config.Jitter
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+func (c *Collector) backoff(failures int) time.Duration {
+	d := c.config.MinBackoff
+	for i := 0; i < failures && d < c.config.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > c.config.MaxBackoff {
+		return c.config.MaxBackoff
+	}
+	return d
+}
+
+func (c *Collector) report(err error) {
+	if c.config.OnError != nil {
+		c.config.OnError(err)
+	}
+}
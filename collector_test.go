@@ -0,0 +1,220 @@
+package rosbotcollector
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClient returns its scripted responses in order, then repeats the last one.
+type fakeClient struct {
+	Client
+	mu        sync.Mutex
+	responses []fakeResponse
+	calls     int
+}
+
+type fakeResponse struct {
+	updates []*ServerUpdate
+	err     error
+}
+
+func (c *fakeClient) next() fakeResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r := c.responses[len(c.responses)-1]
+	if c.calls < len(c.responses) {
+		r = c.responses[c.calls]
+	}
+	c.calls++
+	return r
+}
+
+func (c *fakeClient) ParseWithDefaults(ctx context.Context) ([]*ServerUpdate, error) {
+	return c.ParseWithConfig(ctx, NewParseConfig())
+}
+
+func (c *fakeClient) ParseWithConfig(_ context.Context, _ *ParserConfig) ([]*ServerUpdate, error) {
+	r := c.next()
+	return r.updates, r.err
+}
+
+func (c *fakeClient) ParsePageWithConfig(ctx context.Context, config *ParserConfig) (*ActivityPage, error) {
+	updates, err := c.ParseWithConfig(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return &ActivityPage{Updates: updates, Info: &PageInfo{CurrentPage: FirstPage}}, nil
+}
+
+func updatesWithIDs(ids ...string) []*ServerUpdate {
+	updates := make([]*ServerUpdate, 0, len(ids))
+	for _, id := range ids {
+		updates = append(updates, &ServerUpdate{ID: id})
+	}
+	return updates
+}
+
+func TestCollector_Collect(t *testing.T) {
+	errPoll := errors.New("poll failed")
+
+	tests := []struct {
+		name         string
+		responses    []fakeResponse
+		skipExisting bool
+		seenCapacity int
+		want         []string
+		wantErrors   int
+	}{
+		{
+			name: "New updates only",
+			responses: []fakeResponse{
+				{updates: updatesWithIDs("a", "b")},
+				{updates: updatesWithIDs("a", "b", "c")},
+				{updates: updatesWithIDs("b", "c", "d")},
+			},
+			want: []string{"a", "b", "c", "d"},
+		},
+		{
+			name: "Skip existing",
+			responses: []fakeResponse{
+				{updates: updatesWithIDs("a", "b")},
+				{updates: updatesWithIDs("a", "b", "c")},
+			},
+			skipExisting: true,
+			want:         []string{"c"},
+		},
+		{
+			name: "Failed poll",
+			responses: []fakeResponse{
+				{updates: updatesWithIDs("a")},
+				{err: errPoll},
+				{updates: updatesWithIDs("a", "b")},
+			},
+			want:       []string{"a", "b"},
+			wantErrors: 1,
+		},
+		{
+			name: "Seen capacity",
+			responses: []fakeResponse{
+				{updates: updatesWithIDs("a", "b")},
+				{updates: updatesWithIDs("c")},
+				{updates: updatesWithIDs("a")},
+			},
+			seenCapacity: 2,
+			want:         []string{"a", "b", "c", "a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCollector(&fakeClient{responses: tt.responses}, &CollectorConfig{
+				SkipExisting: tt.skipExisting,
+				SeenCapacity: tt.seenCapacity,
+			})
+
+			var got []string
+			c.Handle(func(_ context.Context, u *ServerUpdate) error {
+				got = append(got, u.ID)
+				return nil
+			})
+
+			errs := 0
+			for range tt.responses {
+				if _, err := c.Collect(context.Background()); err != nil {
+					errs++
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Collect() handled %v, want %v", got, tt.want)
+			}
+			if errs != tt.wantErrors {
+				t.Errorf("Collect() errors = %v, want %v", errs, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestCollector_Run(t *testing.T) {
+	client := &fakeClient{responses: []fakeResponse{
+		{err: errors.New("transient")},
+		{updates: updatesWithIDs("a")},
+		{updates: updatesWithIDs("a", "b")},
+	}}
+
+	var (
+		mu      sync.Mutex
+		handled []string
+		errs    []error
+	)
+	done := make(chan struct{})
+	c := NewCollector(client, &CollectorConfig{
+		Interval:   time.Millisecond,
+		MinBackoff: time.Millisecond,
+		OnError: func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err)
+		},
+	})
+	c.Handle(func(_ context.Context, u *ServerUpdate) error {
+		mu.Lock()
+		defer mu.Unlock()
+		handled = append(handled, u.ID)
+		if len(handled) == 2 {
+			close(done)
+		}
+		return errors.New("handler failed")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error)
+	go func() { result <- c.Run(ctx) }()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Run() did not deliver the updates")
+	}
+	cancel()
+	if err := <-result; err != context.Canceled {
+		t.Errorf("Run() error = %v, want %v", err, context.Canceled)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(handled, []string{"a", "b"}) {
+		t.Errorf("Run() handled %v", handled)
+	}
+	// The failed poll, and both handler errors.
+	if len(errs) != 3 {
+		t.Errorf("Run() reported %v", errs)
+	}
+}
+
+func TestCollector_backoff(t *testing.T) {
+	c := NewCollector(&fakeClient{}, &CollectorConfig{
+		MinBackoff: time.Second,
+		MaxBackoff: 5 * time.Second,
+	})
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for failures, w := range want {
+		if got := c.backoff(failures); got != w {
+			t.Errorf("backoff(%d) = %v, want %v", failures, got, w)
+		}
+	}
+}
+
+func TestCollector_nextInterval(t *testing.T) {
+	c := NewCollector(&fakeClient{}, &CollectorConfig{
+		Interval: time.Minute,
+		Jitter:   10 * time.Second,
+	})
+	for i := 0; i < 100; i++ {
+		if got := c.nextInterval(); got < 50*time.Second || got > 70*time.Second {
+			t.Fatalf("nextInterval() = %v, want within 1m±10s", got)
+		}
+	}
+}
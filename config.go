@@ -0,0 +1,173 @@
+package rosbotcollector
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ParserConfig is the parsing configuration
+type ParserConfig struct {
+	Destinations []Destination
+	RarityLevel  Rarity
+	Quality      Quality
+	// PageNumber is the page to parse. Defaults to the first (newest) page.
+	PageNumber PageNumber
+	// Page is the one-based page to parse; it is only read when `PageNumber` is unset.
+	//
+	// Deprecated: Page is capped at 127 pages, use PageNumber instead.
+	Page int8
+	// Location is the timezone in which the site renders timestamps.
+	// When nil, the client's location is used (UTC unless configured otherwise).
+	Location *time.Location
+	// Filter is applied client-side, on top of the other criteria. See `ParseQuery`.
+	Filter Predicate
+}
+
+// NewParseConfig returns a new instance of `rosbotcollector.ParserConfig` with the default values.
+func NewParseConfig() *ParserConfig {
+	return &ParserConfig{
+		Destinations: []Destination{},
+		RarityLevel:  RarityNonAncient,
+		Quality:      QualityAll,
+		PageNumber:   FirstPage,
+	}
+}
+
+// ErrInvalidPage is returned when the configured page is not a valid one-based page number.
+var ErrInvalidPage = errors.New("page number must be greater than or equal to 1")
+
+// PageIndex returns the zero-based index of the configured page, as expected by the site's
+// '?page=' parameter.
+func (c *ParserConfig) PageIndex() (int, error) {
+	// Compatibility path: `Page` is read when `PageNumber` is unset.
+	n := c.PageNumber
+	if n == 0 {
+		n = PageNumber(c.Page)
+	}
+	if n == 0 {
+		n = FirstPage
+	}
+	if n < FirstPage {
+		return 0, fmt.Errorf("%w: %d", ErrInvalidPage, n)
+	}
+	return n.Index(), nil
+}
+
+// PageNumber is a one-based page number, as displayed by the site's pager (i.e. "Go to page 2").
+// The site's '?page=' parameter is zero-based; see `PageNumber.Index`.
+type PageNumber int
+
+// FirstPage is the first, and newest, page.
+const FirstPage PageNumber = 1
+
+// PageFromIndex returns the page number of a zero-based '?page=' parameter.
+func PageFromIndex(index int) PageNumber {
+	return PageNumber(index + 1)
+}
+
+// Index returns the zero-based '?page=' parameter of the page.
+func (n PageNumber) Index() int {
+	return int(n) - 1
+}
+
+var (
+	// ErrInvalidDestination is returned when a configured destination is not one of
+	// `DestinationStashed`, `DestinationSalvaged` or `DestinationSold`.
+	ErrInvalidDestination = errors.New("invalid destination")
+	// ErrInvalidRarity is returned when the configured rarity is not a known `Rarity`.
+	ErrInvalidRarity = errors.New("invalid rarity")
+	// ErrInvalidQuality is returned when the configured quality is not a known `Quality`.
+	ErrInvalidQuality = errors.New("invalid quality")
+)
+
+// Validate reports whether the configuration can be encoded into the site's search parameters.
+// The returned error wraps one of the `ErrInvalid*` errors.
+func (c *ParserConfig) Validate() error {
+	seen := make(map[Destination]bool, len(c.Destinations))
+	for _, d := range c.Destinations {
+		switch d {
+		case DestinationStashed, DestinationSalvaged, DestinationSold:
+		default:
+			return fmt.Errorf("%w %q: must be one of %q, %q or %q",
+				ErrInvalidDestination, d, DestinationStashed, DestinationSalvaged, DestinationSold)
+		}
+		if seen[d] {
+			return fmt.Errorf("%w %q: listed more than once", ErrInvalidDestination, d)
+		}
+		seen[d] = true
+	}
+
+	switch c.RarityLevel {
+	case RarityPrimal, RarityAncient, RarityNonAncient:
+	default:
+		return fmt.Errorf("%w %q: must be one of %q, %q or %q",
+			ErrInvalidRarity, c.RarityLevel, RarityNonAncient, RarityAncient, RarityPrimal)
+	}
+
+	switch c.Quality {
+	case QualityAll, QualityNormal, QualitySet:
+	default:
+		return fmt.Errorf("%w %q: must be one of %q, %q or %q",
+			ErrInvalidQuality, c.Quality, QualityAll, QualityNormal, QualitySet)
+	}
+
+	_, err := c.PageIndex()
+	return err
+}
+
+// WithDestinations sets the destinations to parse; none means all of them.
+func (c *ParserConfig) WithDestinations(destinations ...Destination) *ParserConfig {
+	c.Destinations = append([]Destination{}, destinations...)
+	return c
+}
+
+// MinRarity sets the minimum rarity to parse, i.e. `RarityAncient` includes primal items.
+func (c *ParserConfig) MinRarity(r Rarity) *ParserConfig {
+	c.RarityLevel = r
+	return c
+}
+
+// WithQuality sets the quality to parse.
+func (c *ParserConfig) WithQuality(q Quality) *ParserConfig {
+	c.Quality = q
+	return c
+}
+
+// WithPage sets the page to parse.
+func (c *ParserConfig) WithPage(n PageNumber) *ParserConfig {
+	c.PageNumber = n
+	return c
+}
+
+// WithLocation sets the timezone in which the site renders timestamps.
+func (c *ParserConfig) WithLocation(loc *time.Location) *ParserConfig {
+	c.Location = loc
+	return c
+}
+
+// WithFilter sets the client-side predicate items must satisfy.
+func (c *ParserConfig) WithFilter(p Predicate) *ParserConfig {
+	c.Filter = p
+	return c
+}
+
+// Predicate returns the criteria of the configuration as a predicate, i.e. to apply them to
+// already collected items: destinations (none means all of them), minimum rarity, quality and
+// `Filter`. Zero criteria match every item.
+func (c *ParserConfig) Predicate() Predicate {
+	predicates := []Predicate{}
+	if len(c.Destinations) > 0 {
+		predicates = append(predicates, DestinationIn(c.Destinations...))
+	}
+	if c.RarityLevel != "" {
+		predicates = append(predicates, RarityAtLeast(c.RarityLevel))
+	}
+	if c.Quality != "" {
+		predicates = append(predicates, QualityIs(c.Quality))
+	}
+	if c.Filter != nil {
+		predicates = append(predicates, c.Filter)
+	}
+	return And(predicates...)
+}
@@ -0,0 +1,129 @@
+package rosbotcollector
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParserConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *ParserConfig
+		wantErr error
+	}{
+		{
+			name:   "Defaults",
+			config: NewParseConfig(),
+		},
+		{
+			name: "Legacy page",
+			config: &ParserConfig{
+				RarityLevel: RarityAncient,
+				Quality:     QualitySet,
+				Page:        127,
+			},
+		},
+		{
+			name:    "Unknown destination",
+			config:  NewParseConfig().WithDestinations(DestinationStashed, DestinationUnknown),
+			wantErr: ErrInvalidDestination,
+		},
+		{
+			name:    "Duplicate destination",
+			config:  NewParseConfig().WithDestinations(DestinationSold, DestinationSold),
+			wantErr: ErrInvalidDestination,
+		},
+		{
+			name:    "Misspelled rarity",
+			config:  NewParseConfig().MinRarity("ANCIENTS"),
+			wantErr: ErrInvalidRarity,
+		},
+		{
+			name:    "Empty rarity",
+			config:  &ParserConfig{Quality: QualityAll},
+			wantErr: ErrInvalidRarity,
+		},
+		{
+			name:    "Misspelled quality",
+			config:  NewParseConfig().WithQuality("LEGENDARY"),
+			wantErr: ErrInvalidQuality,
+		},
+		{
+			name:    "Invalid page",
+			config:  NewParseConfig().WithPage(-2),
+			wantErr: ErrInvalidPage,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParserConfig_builder(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	got := NewParseConfig().
+		WithDestinations(DestinationStashed, DestinationSold).
+		MinRarity(RarityAncient).
+		WithQuality(QualitySet).
+		WithPage(3).
+		WithLocation(loc)
+
+	want := &ParserConfig{
+		Destinations: []Destination{DestinationStashed, DestinationSold},
+		RarityLevel:  RarityAncient,
+		Quality:      QualitySet,
+		PageNumber:   3,
+		Location:     loc,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("builder = %+v, want %+v", got, want)
+	}
+}
+
+func TestParserConfig_Predicate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *ParserConfig
+		want   []string
+	}{
+		{
+			name:   "Defaults",
+			config: NewParseConfig(),
+			want:   []string{"tyrael's might", "unidentified", "captain crimson's trimmings"},
+		},
+		{
+			name:   "Zero",
+			config: &ParserConfig{},
+			want:   []string{"tyrael's might", "unidentified", "captain crimson's trimmings"},
+		},
+		{
+			name:   "Minimum rarity and destinations",
+			config: NewParseConfig().MinRarity(RarityAncient).WithDestinations(DestinationStashed, DestinationSalvaged),
+			want:   []string{"tyrael's might"},
+		},
+		{
+			name:   "Quality",
+			config: NewParseConfig().WithQuality(QualitySet),
+			want:   []string{"captain crimson's trimmings"},
+		},
+		{
+			name:   "Filter",
+			config: NewParseConfig().WithFilter(BotIs("barbarian")),
+			want:   []string{"tyrael's might", "unidentified"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := itemNames(FilterUpdates(testUpdates(), tt.config.Predicate())); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Predicate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package rosbotcollector
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ItemRecord is a legendary item flattened with its server update; one CSV row.
+type ItemRecord struct {
+	UpdateID        string    `json:"update_id"`
+	ServerTimestamp time.Time `json:"server_timestamp"`
+	*LegendaryItem
+}
+
+// FlattenUpdates returns a record per item of the server updates, in order.
+func FlattenUpdates(updates []*ServerUpdate) []*ItemRecord {
+	var records []*ItemRecord
+	for _, u := range updates {
+		for _, item := range u.Items {
+			records = append(records, &ItemRecord{UpdateID: u.ID, ServerTimestamp: u.ServerTimestamp, LegendaryItem: item})
+		}
+	}
+	return records
+}
+
+// CSVColumn is a column of the CSV export.
+type CSVColumn string
+
+const (
+	ColumnUpdateID    CSVColumn = "update_id"
+	ColumnTimestamp   CSVColumn = "timestamp"
+	ColumnItemID      CSVColumn = "item_id"
+	ColumnBot         CSVColumn = "bot"
+	ColumnName        CSVColumn = "name"
+	ColumnRarity      CSVColumn = "rarity"
+	ColumnQuality     CSVColumn = "quality"
+	ColumnDestination CSVColumn = "destination"
+	ColumnIdentified  CSVColumn = "identified"
+	ColumnStats       CSVColumn = "stats"
+)
+
+// DefaultCSVColumns are the columns written when none are configured.
+var DefaultCSVColumns = []CSVColumn{
+	ColumnTimestamp, ColumnBot, ColumnName, ColumnRarity, ColumnQuality, ColumnDestination, ColumnIdentified, ColumnStats,
+}
+
+// ErrInvalidColumn is returned when a CSV column is unknown.
+var ErrInvalidColumn = errors.New("invalid CSV column")
+
+// ParseCSVColumns parses a comma-separated list of columns, i.e. "timestamp,name,rarity".
+func ParseCSVColumns(s string) ([]CSVColumn, error) {
+	var columns []CSVColumn
+	for _, name := range strings.Split(s, ",") {
+		c := CSVColumn(strings.ToLower(strings.TrimSpace(name)))
+		if _, err := c.value(&ItemRecord{LegendaryItem: &LegendaryItem{}}, time.RFC3339); err != nil {
+			return nil, err
+		}
+		columns = append(columns, c)
+	}
+	return columns, nil
+}
+
+func (c CSVColumn) value(r *ItemRecord, timeFormat string) (string, error) {
+	switch c {
+	case ColumnUpdateID:
+		return r.UpdateID, nil
+	case ColumnTimestamp:
+		if r.ServerTimestamp.IsZero() {
+			return "", nil
+		}
+		return r.ServerTimestamp.Format(timeFormat), nil
+	case ColumnItemID:
+		return r.ID, nil
+	case ColumnBot:
+		return r.BotName, nil
+	case ColumnName:
+		return r.Name, nil
+	case ColumnRarity:
+		return string(r.Rarity), nil
+	case ColumnQuality:
+		return string(r.Quality), nil
+	case ColumnDestination:
+		return string(r.Destination), nil
+	case ColumnIdentified:
+		return strconv.FormatBool(r.IsIdentified), nil
+	case ColumnStats:
+		return r.Stats, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidColumn, string(c))
+	}
+}
+
+// CSVWriter writes server updates as CSV, one row per item. Fields are quoted as per RFC 4180;
+// the multiline stats remain a single field.
+type CSVWriter struct {
+	// Columns default to `DefaultCSVColumns`.
+	Columns []CSVColumn
+	// NoHeader omits the header row.
+	NoHeader bool
+	// UseCRLF terminates rows with '\r\n', as RFC 4180 requires, rather than '\n'.
+	UseCRLF bool
+	// TimeFormat is the layout of the timestamp column; defaults to `time.RFC3339`.
+	TimeFormat string
+
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+// NewCSVWriter returns a new instance of `rosbotcollector.CSVWriter`.
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{w: csv.NewWriter(w)}
+}
+
+// Write writes a row per item of the server update, preceded by the header on the first call.
+func (w *CSVWriter) Write(u *ServerUpdate) error {
+	return w.WriteRecords(FlattenUpdates([]*ServerUpdate{u}))
+}
+
+// WriteAll writes every server update, and flushes.
+func (w *CSVWriter) WriteAll(updates []*ServerUpdate) error {
+	if err := w.WriteRecords(FlattenUpdates(updates)); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// WriteRecords writes a row per record, preceded by the header on the first call.
+func (w *CSVWriter) WriteRecords(records []*ItemRecord) error {
+	w.w.UseCRLF = w.UseCRLF
+	columns, timeFormat := w.Columns, w.TimeFormat
+	if len(columns) == 0 {
+		columns = DefaultCSVColumns
+	}
+	if timeFormat == "" {
+		timeFormat = time.RFC3339
+	}
+
+	if !w.wroteHeader {
+		header := make([]string, len(columns))
+		for i, c := range columns {
+			// Validates the columns before anything is written.
+			if _, err := c.value(&ItemRecord{LegendaryItem: &LegendaryItem{}}, timeFormat); err != nil {
+				return err
+			}
+			header[i] = string(c)
+		}
+		if !w.NoHeader {
+			if err := w.w.Write(header); err != nil {
+				return err
+			}
+		}
+		w.wroteHeader = true
+	}
+
+	row := make([]string, len(columns))
+	for _, r := range records {
+		for i, c := range columns {
+			v, err := c.value(r, timeFormat)
+			if err != nil {
+				return err
+			}
+			row[i] = v
+		}
+		if err := w.w.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush writes any buffered data to the underlying writer.
+func (w *CSVWriter) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
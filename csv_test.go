@@ -0,0 +1,137 @@
+package rosbotcollector
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func csvUpdates() []*ServerUpdate {
+	return []*ServerUpdate{
+		{
+			ID:              "u1",
+			ServerTimestamp: time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC),
+			Items: []*LegendaryItem{
+				{
+					ID: "u1-0", Name: "Tyrael's Might", Quality: QualityNormal, Rarity: RarityAncient,
+					Destination: DestinationStashed, IsIdentified: true, BotName: "Barb",
+					Stats: "+10 Strength\n\"Legendary\" power, 20%",
+				},
+				{ID: "u1-1", Name: "Unidentified", Quality: QualitySet, Rarity: RarityNonAncient, Destination: DestinationSalvaged, BotName: "Barb"},
+			},
+		},
+		{ID: "u2"},
+	}
+}
+
+func TestCSVWriter_WriteAll(t *testing.T) {
+	tests := []struct {
+		name    string
+		w       func(*bytes.Buffer) *CSVWriter
+		want    string
+		wantErr error
+	}{
+		{
+			name: "defaults",
+			w:    func(b *bytes.Buffer) *CSVWriter { return NewCSVWriter(b) },
+			want: "timestamp,bot,name,rarity,quality,destination,identified,stats\n" +
+				"2020-05-01T12:00:00Z,Barb,Tyrael's Might,ANCIENT,NORMAL,STASHED,true,\"+10 Strength\n\"\"Legendary\"\" power, 20%\"\n" +
+				"2020-05-01T12:00:00Z,Barb,Unidentified,NON-ANCIENT,SET,SALVAGED,false,\n",
+		},
+		{
+			name: "columns without header with CRLF",
+			w: func(b *bytes.Buffer) *CSVWriter {
+				w := NewCSVWriter(b)
+				w.Columns = []CSVColumn{ColumnItemID, ColumnTimestamp, ColumnName}
+				w.NoHeader = true
+				w.UseCRLF = true
+				w.TimeFormat = "2006-01-02"
+				return w
+			},
+			want: "u1-0,2020-05-01,Tyrael's Might\r\nu1-1,2020-05-01,Unidentified\r\n",
+		},
+		{
+			name: "invalid column",
+			w: func(b *bytes.Buffer) *CSVWriter {
+				w := NewCSVWriter(b)
+				w.Columns = []CSVColumn{ColumnName, "colour"}
+				return w
+			},
+			wantErr: ErrInvalidColumn,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			err := tt.w(&b).WriteAll(csvUpdates())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("WriteAll() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("WriteAll() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVWriter_Write(t *testing.T) {
+	var b bytes.Buffer
+	w := NewCSVWriter(&b)
+	for _, u := range csvUpdates() {
+		if err := w.Write(u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The header is written once, and the multiline stats survive a round trip.
+	rows, err := csv.NewReader(&b).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want 3", len(rows))
+	}
+	if got, want := rows[1][7], csvUpdates()[0].Items[0].Stats; got != want {
+		t.Errorf("stats = %q, want %q", got, want)
+	}
+}
+
+func Test_ParseCSVColumns(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    []CSVColumn
+		wantErr bool
+	}{
+		{s: "timestamp, Name ,rarity", want: []CSVColumn{ColumnTimestamp, ColumnName, ColumnRarity}},
+		{s: "update_id,item_id", want: []CSVColumn{ColumnUpdateID, ColumnItemID}},
+		{s: "name,", wantErr: true},
+		{s: "colour", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := ParseCSVColumns(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCSVColumns() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCSVColumns() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_FlattenUpdates(t *testing.T) {
+	got := FlattenUpdates(csvUpdates())
+	if len(got) != 2 {
+		t.Fatalf("FlattenUpdates() returned %d records, want 2", len(got))
+	}
+	if got[1].UpdateID != "u1" || got[1].ID != "u1-1" || got[1].ServerTimestamp.IsZero() {
+		t.Errorf("FlattenUpdates()[1] = %+v", got[1])
+	}
+}
@@ -0,0 +1,299 @@
+package rosbotcollector
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/smtp"
+	"net/textproto"
+	"sort"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+	"time"
+)
+
+type (
+	// Digest is a summary of the items collected over a period of time.
+	Digest struct {
+		From          time.Time
+		To            time.Time
+		Updates       int
+		Items         int
+		ByRarity      map[Rarity]int
+		ByQuality     map[Quality]int
+		ByDestination map[Destination]int
+		// Notable are the ancient and primal items, oldest first.
+		Notable []*DigestItem
+	}
+
+	// DigestItem is an item, alongside the timestamp of its server update.
+	DigestItem struct {
+		ServerTimestamp time.Time
+		*LegendaryItem
+	}
+
+	// DigestTemplates render the text and HTML bodies of a digest.
+	DigestTemplates struct {
+		Text *texttemplate.Template
+		HTML *htmltemplate.Template
+	}
+)
+
+const (
+	// DefaultDigestTextTemplate is the default plain text body of a digest.
+	DefaultDigestTextTemplate = `Ros-Bot loot digest
+{{.From.Format "2006-01-02 15:04"}} - {{.To.Format "2006-01-02 15:04"}}
+
+{{.Items}} legendary items in {{.Updates}} server updates.
+
+Rarity:
+{{range $k, $v := .ByRarity}}  {{$k}}: {{$v}}
+{{end}}
+Quality:
+{{range $k, $v := .ByQuality}}  {{$k}}: {{$v}}
+{{end}}
+Destination:
+{{range $k, $v := .ByDestination}}  {{$k}}: {{$v}}
+{{end}}{{if .Notable}}
+Ancients and primals:
+{{range .Notable}}  {{.ServerTimestamp.Format "2006-01-02 15:04"}} [{{.Rarity}}] {{.Name}} ({{.BotName}}, {{.Destination}})
+{{end}}{{end}}`
+
+	// DefaultDigestHTMLTemplate is the default HTML body of a digest.
+	DefaultDigestHTMLTemplate = `<html>
+<body>
+<h2>Ros-Bot loot digest</h2>
+<p>{{.From.Format "2006-01-02 15:04"}} - {{.To.Format "2006-01-02 15:04"}}</p>
+<p><strong>{{.Items}}</strong> legendary items in <strong>{{.Updates}}</strong> server updates.</p>
+<table>
+<tr><th colspan="2">Rarity</th></tr>
+{{range $k, $v := .ByRarity}}<tr><td>{{$k}}</td><td>{{$v}}</td></tr>
+{{end}}<tr><th colspan="2">Quality</th></tr>
+{{range $k, $v := .ByQuality}}<tr><td>{{$k}}</td><td>{{$v}}</td></tr>
+{{end}}<tr><th colspan="2">Destination</th></tr>
+{{range $k, $v := .ByDestination}}<tr><td>{{$k}}</td><td>{{$v}}</td></tr>
+{{end}}</table>
+{{if .Notable}}<h3>Ancients and primals</h3>
+<ul>
+{{range .Notable}}<li>{{.ServerTimestamp.Format "2006-01-02 15:04"}} [{{.Rarity}}] <strong>{{.Name}}</strong> ({{.BotName}}, {{.Destination}})</li>
+{{end}}</ul>
+{{end}}</body>
+</html>
+`
+)
+
+// NewDigestTemplates returns the default digest templates.
+func NewDigestTemplates() *DigestTemplates {
+	return &DigestTemplates{
+		Text: texttemplate.Must(texttemplate.New("digest.txt").Parse(DefaultDigestTextTemplate)),
+		HTML: htmltemplate.Must(htmltemplate.New("digest.html").Parse(DefaultDigestHTMLTemplate)),
+	}
+}
+
+// BuildDigest summarises the server updates within [from, to); a zero bound is ignored.
+// When zero, `From` and `To` are set to the oldest and newest server update timestamps.
+func BuildDigest(updates []*ServerUpdate, from, to time.Time) *Digest {
+	d := &Digest{
+		From:          from,
+		To:            to,
+		ByRarity:      make(map[Rarity]int),
+		ByQuality:     make(map[Quality]int),
+		ByDestination: make(map[Destination]int),
+	}
+
+	within := CollectedBetween(from, to)
+	for _, u := range updates {
+		if !within(u, nil) {
+			continue
+		}
+		d.Updates++
+		if from.IsZero() && (d.From.IsZero() || u.ServerTimestamp.Before(d.From)) {
+			d.From = u.ServerTimestamp
+		}
+		if to.IsZero() && u.ServerTimestamp.After(d.To) {
+			d.To = u.ServerTimestamp
+		}
+
+		for _, item := range u.Items {
+			d.Items++
+			d.ByRarity[item.Rarity]++
+			d.ByQuality[item.Quality]++
+			d.ByDestination[item.Destination]++
+			if item.Rarity.rank() >= RarityAncient.rank() {
+				d.Notable = append(d.Notable, &DigestItem{ServerTimestamp: u.ServerTimestamp, LegendaryItem: item})
+			}
+		}
+	}
+
+	sort.SliceStable(d.Notable, func(i, j int) bool {
+		return d.Notable[i].ServerTimestamp.Before(d.Notable[j].ServerTimestamp)
+	})
+	return d
+}
+
+// RenderText renders the plain text body of the digest.
+func (d *Digest) RenderText(w io.Writer, t *DigestTemplates) error {
+	if t == nil {
+		t = NewDigestTemplates()
+	}
+	return t.Text.Execute(w, d)
+}
+
+// RenderHTML renders the HTML body of the digest.
+func (d *Digest) RenderHTML(w io.Writer, t *DigestTemplates) error {
+	if t == nil {
+		t = NewDigestTemplates()
+	}
+	return t.HTML.Execute(w, d)
+}
+
+// SMTPSender emails digests.
+type SMTPSender struct {
+	// Addr is the 'host:port' of the SMTP server.
+	Addr string
+	// Auth is optional, i.e. `smtp.PlainAuth`.
+	Auth    smtp.Auth
+	From    string
+	To      []string
+	Subject string
+	// Templates default to `NewDigestTemplates()`.
+	Templates *DigestTemplates
+}
+
+// Send emails the digest, with both a plain text and an HTML body.
+func (s *SMTPSender) Send(d *Digest) error {
+	msg, err := s.message(d, time.Now())
+	if err != nil {
+		return err
+	}
+	return smtp.SendMail(s.Addr, s.Auth, s.From, s.To, msg)
+}
+
+func (s *SMTPSender) message(d *Digest, date time.Time) ([]byte, error) {
+	t := s.Templates
+	if t == nil {
+		t = NewDigestTemplates()
+	}
+	subject := s.Subject
+	if subject == "" {
+		subject = fmt.Sprintf("Ros-Bot loot digest: %d items", d.Items)
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	parts := []struct {
+		contentType string
+		render      func(io.Writer, *DigestTemplates) error
+	}{
+		// Clients display the last part they support; HTML comes last.
+		{contentType: "text/plain; charset=UTF-8", render: d.RenderText},
+		{contentType: "text/html; charset=UTF-8", render: d.RenderHTML},
+	}
+	for _, p := range parts {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qw := quotedprintable.NewWriter(pw)
+		if err := p.render(qw, t); err != nil {
+			return nil, err
+		}
+		if err := qw.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", s.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(s.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", mw.Boundary())
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+// DigestScheduler buffers collected server updates, and sends a digest of them every period.
+type DigestScheduler struct {
+	Sender *SMTPSender
+	// Period is the time covered by a digest, i.e. `time.Hour` or `24 * time.Hour`.
+	Period time.Duration
+	// SendEmpty sends digests even if no update was collected during the period.
+	SendEmpty bool
+	// OnError is called with every failed send.
+	OnError func(err error)
+
+	mu      sync.Mutex
+	updates []*ServerUpdate
+	since   time.Time
+}
+
+// Add buffers the server update until the next digest. It satisfies `UpdateHandler`.
+func (s *DigestScheduler) Add(_ context.Context, u *ServerUpdate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.updates = append(s.updates, u)
+	return nil
+}
+
+// ErrInvalidPeriod is returned when running a digest scheduler whose period is not positive.
+var ErrInvalidPeriod = errors.New("digest period must be positive")
+
+// Run sends a digest every period, until the context is cancelled, which is the only returned
+// error besides `ErrInvalidPeriod`. Digests are aligned on the period, i.e. every hour on the hour.
+func (s *DigestScheduler) Run(ctx context.Context) error {
+	if s.Period <= 0 {
+		return fmt.Errorf("%w: %v", ErrInvalidPeriod, s.Period)
+	}
+	s.mu.Lock()
+	s.since = time.Now().Truncate(s.Period)
+	s.mu.Unlock()
+
+	for {
+		next := time.Now().Truncate(s.Period).Add(s.Period)
+		if err := sleep(ctx, time.Until(next)); err != nil {
+			return err
+		}
+		if err := s.Flush(next); err != nil && s.OnError != nil {
+			s.OnError(err)
+		}
+	}
+}
+
+// Flush sends a digest of the buffered updates, up to `to`, and empties the buffer. The buffer is
+// kept when the digest could not be sent, so that the next digest covers its updates.
+func (s *DigestScheduler) Flush(to time.Time) error {
+	s.mu.Lock()
+	updates, from := s.updates, s.since
+	s.updates, s.since = nil, to
+	s.mu.Unlock()
+
+	if len(updates) == 0 && !s.SendEmpty {
+		return nil
+	}
+	// The period covers the collection time; updates are dated by the server.
+	d := BuildDigest(updates, time.Time{}, time.Time{})
+	d.From, d.To = from, to
+	if err := s.Sender.Send(d); err != nil {
+		s.mu.Lock()
+		// Updates may have been buffered in the meantime.
+		s.updates, s.since = append(updates, s.updates...), from
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
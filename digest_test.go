@@ -0,0 +1,260 @@
+package rosbotcollector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildDigest(t *testing.T) {
+	tests := []struct {
+		name        string
+		from, to    time.Time
+		want        *Digest
+		wantNotable []string
+	}{
+		{
+			name: "All",
+			want: &Digest{
+				From:          time.Date(2019, 9, 3, 20, 1, 0, 0, time.UTC),
+				To:            time.Date(2019, 9, 3, 21, 58, 0, 0, time.UTC),
+				Updates:       2,
+				Items:         3,
+				ByRarity:      map[Rarity]int{RarityNonAncient: 1, RarityAncient: 1, RarityPrimal: 1},
+				ByQuality:     map[Quality]int{QualityNormal: 2, QualitySet: 1},
+				ByDestination: map[Destination]int{DestinationStashed: 1, DestinationSalvaged: 1, DestinationSold: 1},
+			},
+			wantNotable: []string{"tyrael's might", "captain crimson's trimmings"},
+		},
+		{
+			name: "Period",
+			from: time.Date(2019, 9, 3, 21, 0, 0, 0, time.UTC),
+			to:   time.Date(2019, 9, 3, 22, 0, 0, 0, time.UTC),
+			want: &Digest{
+				From:          time.Date(2019, 9, 3, 21, 0, 0, 0, time.UTC),
+				To:            time.Date(2019, 9, 3, 22, 0, 0, 0, time.UTC),
+				Updates:       1,
+				Items:         1,
+				ByRarity:      map[Rarity]int{RarityPrimal: 1},
+				ByQuality:     map[Quality]int{QualitySet: 1},
+				ByDestination: map[Destination]int{DestinationSold: 1},
+			},
+			wantNotable: []string{"captain crimson's trimmings"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BuildDigest(testUpdates(), tt.from, tt.to)
+
+			var notable []string
+			for _, item := range got.Notable {
+				notable = append(notable, item.Name)
+			}
+			if !reflect.DeepEqual(notable, tt.wantNotable) {
+				t.Errorf("BuildDigest() notable = %v, want %v", notable, tt.wantNotable)
+			}
+			got.Notable = nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BuildDigest() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDigest_Render(t *testing.T) {
+	d := BuildDigest(testUpdates(), time.Time{}, time.Time{})
+
+	var text, html bytes.Buffer
+	if err := d.RenderText(&text, nil); err != nil {
+		t.Fatalf("RenderText() error = %v", err)
+	}
+	if err := d.RenderHTML(&html, nil); err != nil {
+		t.Fatalf("RenderHTML() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"3 legendary items in 2 server updates.",
+		"  ANCIENT: 1\n",
+		"  SET: 1\n",
+		"  SOLD: 1\n",
+		"  2019-09-03 21:58 [PRIMAL] captain crimson's trimmings (Crusader, SOLD)\n",
+	} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("RenderText() = %s, want it to contain %q", text.String(), want)
+		}
+	}
+	// Names are escaped.
+	if !strings.Contains(html.String(), "<strong>tyrael&#39;s might</strong>") {
+		t.Errorf("RenderHTML() = %s", html.String())
+	}
+}
+
+// smtpStub accepts a single message, and sends it on the returned channel.
+func smtpStub(t *testing.T) (string, <-chan []byte) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not listen: %v", err)
+	}
+	received := make(chan []byte, 1)
+
+	go func() {
+		defer l.Close()
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		tp := textproto.NewConn(conn)
+		_ = tp.PrintfLine("220 localhost ESMTP stub")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			switch cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); cmd {
+			case "EHLO", "HELO":
+				_ = tp.PrintfLine("250 localhost")
+			case "DATA":
+				_ = tp.PrintfLine("354 end with <CR><LF>.<CR><LF>")
+				data, err := ioutil.ReadAll(tp.DotReader())
+				if err != nil {
+					return
+				}
+				received <- data
+				_ = tp.PrintfLine("250 OK")
+			case "QUIT":
+				_ = tp.PrintfLine("221 bye")
+				return
+			default:
+				_ = tp.PrintfLine("250 OK")
+			}
+		}
+	}()
+	return l.Addr().String(), received
+}
+
+func TestSMTPSender_Send(t *testing.T) {
+	addr, received := smtpStub(t)
+	s := &SMTPSender{
+		Addr: addr,
+		From: "collector@example.com",
+		To:   []string{"team@example.com", "lead@example.com"},
+	}
+	if err := s.Send(BuildDigest(testUpdates(), time.Time{}, time.Time{})); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	var data []byte
+	select {
+	case data = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Send() did not deliver the message")
+	}
+
+	msg, err := mail.ReadMessage(bufio.NewReader(bytes.NewReader(data)))
+	if err != nil {
+		t.Fatalf("invalid message: %v", err)
+	}
+	if got := msg.Header.Get("Subject"); got != "Ros-Bot loot digest: 3 items" {
+		t.Errorf("Send() subject = %v", got)
+	}
+	if got := msg.Header.Get("To"); got != "team@example.com, lead@example.com" {
+		t.Errorf("Send() to = %v", got)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Send() content type = %v (%v)", mediaType, err)
+	}
+	var types []string
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		p, err := mr.NextPart()
+		if err != nil {
+			break
+		}
+		body, _ := ioutil.ReadAll(p)
+		if !strings.Contains(string(body), "captain crimson") {
+			t.Errorf("Send() part %v = %s", p.Header.Get("Content-Type"), body)
+		}
+		types = append(types, p.Header.Get("Content-Type"))
+	}
+	if want := []string{"text/plain; charset=UTF-8", "text/html; charset=UTF-8"}; !reflect.DeepEqual(types, want) {
+		t.Errorf("Send() parts = %v, want %v", types, want)
+	}
+}
+
+func TestDigestScheduler_Flush(t *testing.T) {
+	addr, received := smtpStub(t)
+	s := &DigestScheduler{
+		Sender: &SMTPSender{Addr: addr, From: "collector@example.com", To: []string{"team@example.com"}},
+		Period: time.Hour,
+	}
+
+	// Nothing buffered, nothing sent.
+	if err := s.Flush(time.Now()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	for _, u := range testUpdates() {
+		_ = s.Add(context.Background(), u)
+	}
+	if err := s.Flush(time.Now()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	select {
+	case <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Flush() did not deliver the message")
+	}
+	if len(s.updates) != 0 {
+		t.Errorf("Flush() did not empty the buffer")
+	}
+}
+
+func TestDigestScheduler_Flush_sendError(t *testing.T) {
+	// Nothing listens on the address of a closed listener.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	since := time.Date(2019, 9, 3, 21, 0, 0, 0, time.UTC)
+	s := &DigestScheduler{
+		Sender: &SMTPSender{Addr: addr, From: "collector@example.com", To: []string{"team@example.com"}},
+		Period: time.Hour,
+		since:  since,
+	}
+	for _, u := range testUpdates() {
+		_ = s.Add(context.Background(), u)
+	}
+	if err := s.Flush(since.Add(time.Hour)); err == nil {
+		t.Fatalf("Flush() error = nil, want a send error")
+	}
+	if len(s.updates) != len(testUpdates()) || !s.since.Equal(since) {
+		t.Errorf("Flush() buffer = %d updates since %v, want %d since %v", len(s.updates), s.since, len(testUpdates()), since)
+	}
+}
+
+func TestDigestScheduler_Run_invalidPeriod(t *testing.T) {
+	for _, period := range []time.Duration{0, -time.Hour} {
+		s := &DigestScheduler{Sender: &SMTPSender{}, Period: period}
+		if err := s.Run(context.Background()); !errors.Is(err, ErrInvalidPeriod) {
+			t.Errorf("Run() error = %v, want %v", err, ErrInvalidPeriod)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package rosbotcollector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// dropStatisticsPath is the public page of the global drop statistics.
+const dropStatisticsPath = "/items-drop-statistics"
+
+// DropStatistics are the global legendary drop figures, across every account of the site, as
+// displayed by the '/items-drop-statistics' page.
+type DropStatistics struct {
+	// Total is the sum of the drops of every rarity.
+	Total    int            `json:"total"`
+	Rarities []*RarityDrops `json:"rarities"`
+	Items    []*ItemDrops   `json:"items"`
+}
+
+// RarityDrops are the drops of a rarity.
+type RarityDrops struct {
+	Rarity Rarity `json:"rarity"`
+	Drops  int    `json:"drops"`
+	// Share is the fraction of every legendary drop which is of the rarity.
+	Share float64 `json:"share"`
+}
+
+// ItemDrops are the drops of an item, broken down by rarity.
+type ItemDrops struct {
+	Name     string         `json:"name"`
+	Drops    int            `json:"drops"`
+	Rarities map[Rarity]int `json:"rarities"`
+	// Share is the fraction of every legendary drop which is of the item.
+	Share float64 `json:"share"`
+}
+
+// ErrNoDropStatistics is returned when the drop statistics could not be parsed from response body,
+// and when comparing drop rates without any.
+var ErrNoDropStatistics = errors.New("could not parse drop statistics from response body")
+
+func (c *client) DropStatistics(ctx context.Context) (*DropStatistics, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	// The page is public; the session is not needed, nor refreshed.
+	res, err := c.httpService.GetPublicPage(dropStatisticsPath)
+	if err != nil {
+		return nil, err
+	}
+	return parseDropStatistics(res.Body)
+}
+
+func parseDropStatistics(body io.ReadCloser) (*DropStatistics, error) {
+	doc, err := goquery.NewDocumentFromReader(body)
+	if err != nil {
+		return nil, err
+	}
+	_ = body.Close()
+
+	s := &DropStatistics{Rarities: make([]*RarityDrops, 0), Items: make([]*ItemDrops, 0)}
+	eachTableRow(doc.Find("table.drop-statistics-rarities"), func(r *tableRow) {
+		rarity, ok := parseQueryRarity(r.text("rarity"))
+		drops, err := parseCount(r.text("drops"))
+		if !ok || err != nil {
+			return
+		}
+		s.Rarities = append(s.Rarities, &RarityDrops{Rarity: rarity, Drops: drops})
+		s.Total += drops
+	})
+	// Precaution.
+	if s.Total == 0 {
+		return nil, ErrNoDropStatistics
+	}
+	for _, r := range s.Rarities {
+		r.Share = float64(r.Drops) / float64(s.Total)
+	}
+
+	eachTableRow(doc.Find("table.drop-statistics-items"), func(r *tableRow) {
+		item := &ItemDrops{Name: r.text("item", "name"), Rarities: make(map[Rarity]int)}
+		sum := 0
+		for _, rarity := range []Rarity{RarityNonAncient, RarityAncient, RarityPrimal} {
+			if n, err := parseCount(r.text(strings.ToLower(string(rarity)))); err == nil {
+				item.Rarities[rarity] = n
+				sum += n
+			}
+		}
+		// The total falls back onto the sum of the rarities.
+		drops, err := parseCount(r.text("drops"))
+		if err != nil {
+			drops = sum
+		}
+		if item.Name == "" || drops == 0 {
+			return
+		}
+		item.Drops = drops
+		item.Share = float64(drops) / float64(s.Total)
+		s.Items = append(s.Items, item)
+	})
+	return s, nil
+}
+
+var countRegex = regexp.MustCompile(`\d[\d,]*`)
+
+// parseCount parses the first count of the text, i.e. "12,345 (0.6%)".
+func parseCount(raw string) (int, error) {
+	n, err := strconv.Atoi(strings.ReplaceAll(countRegex.FindString(raw), ",", ""))
+	if err != nil {
+		return 0, fmt.Errorf("invalid count %q", raw)
+	}
+	return n, nil
+}
+
+// DropComparison contrasts the drop rates of an account with the global ones.
+type DropComparison struct {
+	Total       int                   `json:"total"`
+	GlobalTotal int                   `json:"global_total"`
+	Rarities    []*DropRateComparison `json:"rarities"`
+	// Items are the items dropped by the account, most dropped first.
+	Items []*DropRateComparison `json:"items"`
+}
+
+// DropRateComparison contrasts the share of the account's drops which are of a rarity, or of an
+// item, with the global share.
+type DropRateComparison struct {
+	// Key is the rarity, or the item name.
+	Key         string  `json:"key"`
+	Drops       int     `json:"drops"`
+	Share       float64 `json:"share"`
+	GlobalDrops int     `json:"global_drops"`
+	GlobalShare float64 `json:"global_share"`
+	// Ratio is `Share / GlobalShare`: below 1, the account drops less of it than the site does
+	// on average. It is zero when the site has no record of it.
+	Ratio float64 `json:"ratio"`
+}
+
+// CompareDropRates contrasts the drop rates of the items of the server updates with the global
+// drop statistics. Item names are matched case-insensitively.
+//
+// The server updates must be parsed without any filtering, i.e. with `NewParseConfig()`: the
+// global statistics cover every legendary drop, hence so must the account's shares.
+func CompareDropRates(updates []*ServerUpdate, global *DropStatistics) (*DropComparison, error) {
+	if global == nil || global.Total == 0 {
+		return nil, ErrNoDropStatistics
+	}
+	c := &DropComparison{GlobalTotal: global.Total}
+	rarities := make(map[Rarity]int)
+	items := make(map[string]*DropRateComparison)
+	for _, u := range updates {
+		for _, item := range u.Items {
+			c.Total++
+			rarities[item.Rarity]++
+			key := strings.ToLower(strings.TrimSpace(item.Name))
+			if items[key] == nil {
+				items[key] = &DropRateComparison{Key: item.Name}
+			}
+			items[key].Drops++
+		}
+	}
+
+	globalRarities := make(map[Rarity]*RarityDrops, len(global.Rarities))
+	for _, r := range global.Rarities {
+		globalRarities[r.Rarity] = r
+	}
+	for _, rarity := range []Rarity{RarityNonAncient, RarityAncient, RarityPrimal} {
+		cmp := &DropRateComparison{Key: string(rarity), Drops: rarities[rarity]}
+		if g := globalRarities[rarity]; g != nil {
+			cmp.GlobalDrops, cmp.GlobalShare = g.Drops, g.Share
+		}
+		c.Rarities = append(c.Rarities, cmp.compute(c.Total))
+	}
+
+	for _, g := range global.Items {
+		if cmp := items[strings.ToLower(g.Name)]; cmp != nil {
+			cmp.GlobalDrops, cmp.GlobalShare = g.Drops, g.Share
+		}
+	}
+	c.Items = make([]*DropRateComparison, 0, len(items))
+	for _, cmp := range items {
+		c.Items = append(c.Items, cmp.compute(c.Total))
+	}
+	sort.Slice(c.Items, func(i, j int) bool {
+		if c.Items[i].Drops != c.Items[j].Drops {
+			return c.Items[i].Drops > c.Items[j].Drops
+		}
+		return c.Items[i].Key < c.Items[j].Key
+	})
+	return c, nil
+}
+
+// compute sets the share and ratio, given the total drops of the account.
+func (c *DropRateComparison) compute(total int) *DropRateComparison {
+	if total > 0 {
+		c.Share = float64(c.Drops) / float64(total)
+	}
+	if c.GlobalShare > 0 {
+		c.Ratio = c.Share / c.GlobalShare
+	}
+	return c
+}
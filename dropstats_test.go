@@ -0,0 +1,151 @@
+package rosbotcollector
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"math"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_parseDropStatistics(t *testing.T) {
+	file, err := os.Open("./samples/items_drop_statistics.html")
+	if err != nil {
+		t.Fatalf("could not open html file")
+	}
+	defer file.Close()
+
+	got, err := parseDropStatistics(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &DropStatistics{
+		Total: 2000000,
+		Rarities: []*RarityDrops{
+			{Rarity: RarityNonAncient, Drops: 1800000, Share: 0.9},
+			{Rarity: RarityAncient, Drops: 190000, Share: 0.095},
+			{Rarity: RarityPrimal, Drops: 10000, Share: 0.005},
+		},
+		Items: []*ItemDrops{
+			{Name: "Furnace", Drops: 20000, Share: 0.01, Rarities: map[Rarity]int{RarityNonAncient: 18000, RarityAncient: 1900, RarityPrimal: 100}},
+			{Name: "Stone of Jordan", Drops: 10000, Share: 0.005, Rarities: map[Rarity]int{RarityNonAncient: 9000, RarityAncient: 950, RarityPrimal: 50}},
+			// The total is missing, hence summed up.
+			{Name: "Tyrael's Might", Drops: 5525, Share: 0.0027625, Rarities: map[Rarity]int{RarityNonAncient: 5000, RarityAncient: 500, RarityPrimal: 25}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDropStatistics() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_parseDropStatistics_empty(t *testing.T) {
+	body := ioutil.NopCloser(strings.NewReader("<html><body>Maintenance</body></html>"))
+	if _, err := parseDropStatistics(body); !errors.Is(err, ErrNoDropStatistics) {
+		t.Errorf("parseDropStatistics() error = %v, want %v", err, ErrNoDropStatistics)
+	}
+}
+
+func Test_parseCount(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    int
+		wantErr bool
+	}{
+		{raw: "1,800,000", want: 1800000},
+		{raw: " 42 ", want: 42},
+		{raw: "12,345 (0.6%)", want: 12345},
+		{raw: "n/a", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := parseCount(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseCount() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_CompareDropRates(t *testing.T) {
+	global := &DropStatistics{
+		Total: 1000,
+		Rarities: []*RarityDrops{
+			{Rarity: RarityNonAncient, Drops: 900, Share: 0.9},
+			{Rarity: RarityAncient, Drops: 100, Share: 0.1},
+		},
+		Items: []*ItemDrops{
+			{Name: "Furnace", Drops: 50, Share: 0.05},
+			{Name: "Stone of Jordan", Drops: 20, Share: 0.02},
+		},
+	}
+	updates := []*ServerUpdate{
+		{Items: []*LegendaryItem{
+			{Name: "Furnace", Rarity: RarityAncient},
+			{Name: "furnace", Rarity: RarityNonAncient},
+		}},
+		{Items: []*LegendaryItem{
+			{Name: "Stone of Jordan", Rarity: RarityNonAncient},
+			{Name: "Unity", Rarity: RarityPrimal},
+		}},
+	}
+
+	got, err := CompareDropRates(updates, global)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Total != 4 || got.GlobalTotal != 1000 {
+		t.Errorf("CompareDropRates() totals = %d, %d, want 4, 1000", got.Total, got.GlobalTotal)
+	}
+	type row struct {
+		key   string
+		drops int
+		ratio float64
+	}
+	rows := func(cs []*DropRateComparison) []row {
+		var rows []row
+		for _, c := range cs {
+			rows = append(rows, row{c.Key, c.Drops, math.Round(c.Ratio*100) / 100})
+		}
+		return rows
+	}
+	// An ancient out of 4 drops is 2.5 times the global rate; primals are not on record.
+	if want := []row{{"NON-ANCIENT", 2, 0.56}, {"ANCIENT", 1, 2.5}, {"PRIMAL", 1, 0}}; !reflect.DeepEqual(rows(got.Rarities), want) {
+		t.Errorf("CompareDropRates() rarities = %v, want %v", rows(got.Rarities), want)
+	}
+	if want := []row{{"Furnace", 2, 10}, {"Stone of Jordan", 1, 12.5}, {"Unity", 1, 0}}; !reflect.DeepEqual(rows(got.Items), want) {
+		t.Errorf("CompareDropRates() items = %v, want %v", rows(got.Items), want)
+	}
+}
+
+func Test_CompareDropRates_noStatistics(t *testing.T) {
+	for _, global := range []*DropStatistics{nil, {}} {
+		if _, err := CompareDropRates(nil, global); !errors.Is(err, ErrNoDropStatistics) {
+			t.Errorf("CompareDropRates(%v) error = %v, want %v", global, err, ErrNoDropStatistics)
+		}
+	}
+}
+
+func TestClient_DropStatistics(t *testing.T) {
+	page, err := ioutil.ReadFile("./samples/items_drop_statistics.html")
+	if err != nil {
+		t.Fatalf("could not open html file")
+	}
+	// Served as a public page only.
+	s := &fakeHTTPService{public: map[string]string{"/items-drop-statistics": string(page)}}
+	c := &client{httpService: s, location: time.UTC}
+
+	got, err := c.DropStatistics(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Total != 2000000 || len(got.Items) != 3 {
+		t.Errorf("DropStatistics() = %+v", got)
+	}
+}
@@ -0,0 +1,172 @@
+package rosbotcollector
+
+import (
+	"strings"
+	"time"
+)
+
+// Predicate reports whether an item, collected as part of the given server update, satisfies a
+// condition. Predicates are combined with `And`, `Or` and `Not`.
+type Predicate func(u *ServerUpdate, item *LegendaryItem) bool
+
+// And returns a predicate satisfied when all of the given predicates are.
+func And(predicates ...Predicate) Predicate {
+	return func(u *ServerUpdate, item *LegendaryItem) bool {
+		for _, p := range predicates {
+			if !p(u, item) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or returns a predicate satisfied when any of the given predicates is.
+func Or(predicates ...Predicate) Predicate {
+	return func(u *ServerUpdate, item *LegendaryItem) bool {
+		for _, p := range predicates {
+			if p(u, item) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not returns a predicate satisfied when the given predicate is not.
+func Not(p Predicate) Predicate {
+	return func(u *ServerUpdate, item *LegendaryItem) bool {
+		return !p(u, item)
+	}
+}
+
+// NameIs matches items named `name`; case-insensitive.
+func NameIs(name string) Predicate {
+	return func(_ *ServerUpdate, item *LegendaryItem) bool {
+		return strings.EqualFold(item.Name, name)
+	}
+}
+
+// NameContains matches items whose name contains `sub`; case-insensitive.
+func NameContains(sub string) Predicate {
+	sub = strings.ToLower(sub)
+	return func(_ *ServerUpdate, item *LegendaryItem) bool {
+		return strings.Contains(strings.ToLower(item.Name), sub)
+	}
+}
+
+// RarityIs matches items of the given rarities.
+func RarityIs(rarities ...Rarity) Predicate {
+	return func(_ *ServerUpdate, item *LegendaryItem) bool {
+		for _, r := range rarities {
+			if item.Rarity == r {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// RarityAtLeast matches items of rarity `r` or above, i.e. `RarityAncient` matches primal items.
+func RarityAtLeast(r Rarity) Predicate {
+	return func(_ *ServerUpdate, item *LegendaryItem) bool {
+		return item.Rarity.rank() >= r.rank()
+	}
+}
+
+// RarityAtMost matches items of rarity `r` or below.
+func RarityAtMost(r Rarity) Predicate {
+	return func(_ *ServerUpdate, item *LegendaryItem) bool {
+		return item.Rarity.rank() <= r.rank()
+	}
+}
+
+// QualityIs matches items of the given qualities; `QualityAll` matches every item.
+func QualityIs(qualities ...Quality) Predicate {
+	return func(_ *ServerUpdate, item *LegendaryItem) bool {
+		for _, q := range qualities {
+			if q == QualityAll || item.Quality == q {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// DestinationIn matches items placed in any of the given destinations.
+func DestinationIn(destinations ...Destination) Predicate {
+	return func(_ *ServerUpdate, item *LegendaryItem) bool {
+		return contains(destinations, item.Destination)
+	}
+}
+
+// Identified matches items whose identified state is `identified`.
+func Identified(identified bool) Predicate {
+	return func(_ *ServerUpdate, item *LegendaryItem) bool {
+		return item.IsIdentified == identified
+	}
+}
+
+// BotIs matches items collected by the bot named `name`; case-insensitive.
+func BotIs(name string) Predicate {
+	return func(_ *ServerUpdate, item *LegendaryItem) bool {
+		return strings.EqualFold(item.BotName, name)
+	}
+}
+
+// BotContains matches items collected by a bot whose name contains `sub`; case-insensitive.
+func BotContains(sub string) Predicate {
+	sub = strings.ToLower(sub)
+	return func(_ *ServerUpdate, item *LegendaryItem) bool {
+		return strings.Contains(strings.ToLower(item.BotName), sub)
+	}
+}
+
+// CollectedBetween matches items whose server update timestamp is within [from, to).
+// A zero bound is ignored.
+func CollectedBetween(from, to time.Time) Predicate {
+	return func(u *ServerUpdate, _ *LegendaryItem) bool {
+		if !from.IsZero() && u.ServerTimestamp.Before(from) {
+			return false
+		}
+		if !to.IsZero() && !u.ServerTimestamp.Before(to) {
+			return false
+		}
+		return true
+	}
+}
+
+// StatsContain matches items whose stats contain `sub`; case-insensitive.
+func StatsContain(sub string) Predicate {
+	sub = strings.ToLower(sub)
+	return func(_ *ServerUpdate, item *LegendaryItem) bool {
+		return strings.Contains(strings.ToLower(item.Stats), sub)
+	}
+}
+
+// FilterUpdates returns the server updates holding at least one item which satisfies `p`.
+// The returned updates are copies only holding the matching items; the originals are untouched.
+func FilterUpdates(updates []*ServerUpdate, p Predicate) []*ServerUpdate {
+	result := make([]*ServerUpdate, 0, len(updates))
+	for _, u := range updates {
+		items := filter(u.Items, func(item *LegendaryItem) bool { return p(u, item) })
+		if len(items) == 0 {
+			continue
+		}
+		c := *u
+		c.Items = items
+		result = append(result, &c)
+	}
+	return result
+}
+
+func (r Rarity) rank() int {
+	switch r {
+	case RarityPrimal:
+		return 2
+	case RarityAncient:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,125 @@
+package rosbotcollector
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testUpdates() []*ServerUpdate {
+	return []*ServerUpdate{
+		{
+			ServerTimestamp: time.Date(2019, 9, 3, 20, 1, 0, 0, time.UTC),
+			Items: []*LegendaryItem{
+				{
+					Name:         "tyrael's might",
+					IsIdentified: true,
+					Quality:      QualityNormal,
+					Rarity:       RarityAncient,
+					Destination:  DestinationStashed,
+					Stats:        "Armor\n736\nPrimary\n+474 Dexterity",
+					BotName:      "Barbarian",
+				},
+				{
+					Name:         "unidentified",
+					IsIdentified: false,
+					Quality:      QualityNormal,
+					Rarity:       RarityNonAncient,
+					Destination:  DestinationSalvaged,
+					BotName:      "Barbarian",
+				},
+			},
+		},
+		{
+			ServerTimestamp: time.Date(2019, 9, 3, 21, 58, 0, 0, time.UTC),
+			Items: []*LegendaryItem{
+				{
+					Name:         "captain crimson's trimmings",
+					IsIdentified: true,
+					Quality:      QualitySet,
+					Rarity:       RarityPrimal,
+					Destination:  DestinationSold,
+					Stats:        "Armor\n912\nPrimary\n+650 Strength",
+					BotName:      "Crusader",
+				},
+			},
+		},
+	}
+}
+
+func itemNames(updates []*ServerUpdate) []string {
+	var names []string
+	for _, u := range updates {
+		for _, item := range u.Items {
+			names = append(names, item.Name)
+		}
+	}
+	return names
+}
+
+func TestFilterUpdates(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Predicate
+		want []string
+	}{
+		{
+			name: "Rarity at least ancient",
+			p:    RarityAtLeast(RarityAncient),
+			want: []string{"tyrael's might", "captain crimson's trimmings"},
+		},
+		{
+			name: "And",
+			p:    And(RarityAtLeast(RarityAncient), DestinationIn(DestinationStashed)),
+			want: []string{"tyrael's might"},
+		},
+		{
+			name: "Or",
+			p:    Or(NameContains("TYRAEL"), BotIs("crusader")),
+			want: []string{"tyrael's might", "captain crimson's trimmings"},
+		},
+		{
+			name: "Not",
+			p:    Not(Identified(true)),
+			want: []string{"unidentified"},
+		},
+		{
+			name: "Quality all",
+			p:    QualityIs(QualityAll),
+			want: []string{"tyrael's might", "unidentified", "captain crimson's trimmings"},
+		},
+		{
+			name: "Collected between",
+			p: CollectedBetween(
+				time.Date(2019, 9, 3, 21, 0, 0, 0, time.UTC),
+				time.Date(2019, 9, 3, 22, 0, 0, 0, time.UTC),
+			),
+			want: []string{"captain crimson's trimmings"},
+		},
+		{
+			name: "Stats contain",
+			p:    StatsContain("dexterity"),
+			want: []string{"tyrael's might"},
+		},
+		{
+			name: "No match",
+			p:    NameIs("the furnace"),
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := itemNames(FilterUpdates(testUpdates(), tt.p)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FilterUpdates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterUpdates_originalsUntouched(t *testing.T) {
+	updates := testUpdates()
+	FilterUpdates(updates, NameIs("unidentified"))
+	if len(updates[0].Items) != 2 {
+		t.Errorf("FilterUpdates() modified the original update")
+	}
+}
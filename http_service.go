@@ -20,8 +20,16 @@ type (
 	HTTPService interface {
 		// Authenticate posts the user credentials, and places the resulting cookies in a jar.
 		Authenticate() (HTTPService, error)
-		// GetActivity retrieves the page body of 'user/{user_id}/bot-activity'.
-		GetActivity(searchSegment string) (io.ReadCloser, error)
+		// GetActivity retrieves the page 'user/{user_id}/bot-activity'.
+		// The response headers are kept as they carry the server's clock.
+		GetActivity(searchSegment string) (*http.Response, error)
+		// GetUserPage retrieves the page 'user/{user_id}/{segment}'.
+		GetUserPage(segment string) (*http.Response, error)
+		// GetPage retrieves any page of the site, given its path, i.e. a file download.
+		GetPage(path string) (*http.Response, error)
+		// GetPublicPage retrieves a page of the site which requires no authentication, given its
+		// path, i.e. '/items-drop-statistics'.
+		GetPublicPage(path string) (*http.Response, error)
 	}
 
 	httpService struct {
@@ -36,7 +44,9 @@ type (
 	}
 
 	endpoints struct {
+		Base     string
 		Login    string
+		User     string
 		Activity string
 	}
 )
@@ -46,7 +56,7 @@ const (
 	loginEndpoint = "/user/login"
 )
 
-func newHTTPService(usernameOrEmail, password string) (HTTPService, error) {
+func newHTTPService(usernameOrEmail, password string, transport http.RoundTripper) (HTTPService, error) {
 	jar, _ := cookiejar.New(nil)
 	s := &httpService{
 		credentials: &credentials{
@@ -54,10 +64,12 @@ func newHTTPService(usernameOrEmail, password string) (HTTPService, error) {
 			Password:        password,
 		},
 		client: &http.Client{
-			Jar:     jar,
-			Timeout: 10 * time.Second,
+			Transport: transport,
+			Jar:       jar,
+			Timeout:   10 * time.Second,
 		},
 		endpoints: &endpoints{
+			Base:     baseURL,
 			Login:    baseURL + loginEndpoint,
 			Activity: baseURL,
 		},
@@ -78,7 +90,8 @@ func (s *httpService) Authenticate() (HTTPService, error) {
 	if err != nil {
 		return nil, err
 	}
-	s.endpoints.Activity += activityEndpoint
+	s.endpoints.Activity = baseURL + activityEndpoint
+	s.endpoints.User = baseURL + strings.TrimSuffix(activityEndpoint, "/bot-activity")
 
 	return s, nil
 }
@@ -93,29 +106,79 @@ var (
 	ErrNoActivityEndpoint = errors.New("could not parse bot activity endpoint from response body")
 	// ErrCookiesRefresh is returned when the attempt to refresh user cookies has failed.
 	ErrCookiesRefresh = errors.New("error refreshing cookies")
+	// ErrUnexpectedStatus is returned when a page could not be retrieved, even after refreshing
+	// the user cookies.
+	ErrUnexpectedStatus = errors.New("unexpected response status")
+	// ErrNoTimezone is returned when the account timezone could not be parsed from response body.
+	ErrNoTimezone = errors.New("could not parse account timezone from response body")
 )
 
-func (s *httpService) GetActivity(searchSegment string) (io.ReadCloser, error) {
-	req, err := http.NewRequest(http.MethodGet, s.endpoints.Activity+searchSegment, nil)
+func (s *httpService) GetActivity(searchSegment string) (*http.Response, error) {
+	return s.getAuthenticated(s.endpoints.Activity + searchSegment)
+}
+
+func (s *httpService) GetUserPage(segment string) (*http.Response, error) {
+	return s.getAuthenticated(s.endpoints.User + "/" + segment)
+}
+
+func (s *httpService) GetPage(path string) (*http.Response, error) {
+	return s.getAuthenticated(s.endpoints.Base + path)
+}
+
+func (s *httpService) GetPublicPage(path string) (*http.Response, error) {
+	res, err := s.get(s.endpoints.Base + path)
 	if err != nil {
 		return nil, err
 	}
-	res, err := s.client.Do(req)
+	if res.StatusCode != http.StatusOK {
+		_ = res.Body.Close()
+		return nil, fmt.Errorf("%w: %s", ErrUnexpectedStatus, res.Status)
+	}
+	return res, nil
+}
+
+// getAuthenticated retrieves a page only accessible to authenticated users.
+func (s *httpService) getAuthenticated(url string) (*http.Response, error) {
+	res, err := s.get(url)
 	if err != nil {
 		return nil, err
 	}
+	if res.StatusCode == http.StatusOK && !s.isLoginPage(res) {
+		return res, nil
+	}
+	_ = res.Body.Close()
 
 	// If the client instance is used for a long period of time,
 	// the session cookies might be expired.
-	if res.StatusCode != 200 {
-		body, err := s.postForm()
-		if err != nil {
-			panic(fmt.Sprintf("%v: %v", ErrCookiesRefresh, err))
-		}
-		_ = body.Close()
+	body, err := s.postForm()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCookiesRefresh, err)
 	}
+	_ = body.Close()
 
-	return res.Body, nil
+	res, err = s.get(url)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK || s.isLoginPage(res) {
+		_ = res.Body.Close()
+		return nil, fmt.Errorf("%w: %s", ErrUnexpectedStatus, res.Status)
+	}
+	return res, nil
+}
+
+func (s *httpService) get(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.Do(req)
+}
+
+// isLoginPage reports whether the request was redirected to the login page; the server does so
+// whenever the session has expired.
+func (s *httpService) isLoginPage(res *http.Response) bool {
+	return res.Request != nil && res.Request.URL.String() == s.endpoints.Login
 }
 
 func (s *httpService) postForm() (io.ReadCloser, error) {
@@ -140,7 +203,7 @@ func (s *httpService) postForm() (io.ReadCloser, error) {
 
 	// Login using the user credentials.
 	req, _ = http.NewRequest(http.MethodPost, s.endpoints.Login, strings.NewReader(form.Encode()))
-	req.Header.Add("Content-Quality", "application/x-www-form-urlencoded")
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 	res, err = s.client.Do(req)
 	if err != nil {
 		return nil, err
@@ -210,3 +273,23 @@ func parseActivityEndpoint(body io.ReadCloser) (result string, err error) {
 	}
 	return
 }
+
+func parseTimezone(body io.ReadCloser) (*time.Location, error) {
+	doc, err := goquery.NewDocumentFromReader(body)
+	if err != nil {
+		return nil, err
+	}
+	_ = body.Close()
+
+	// The account timezone is the selected option of the 'Locale settings' fieldset.
+	name, _ := doc.
+		Find("select[name=timezone]").
+		Find("option[selected]").
+		Attr("value")
+
+	// Precaution.
+	if name == "" {
+		return nil, ErrNoTimezone
+	}
+	return time.LoadLocation(name)
+}
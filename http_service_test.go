@@ -1,6 +1,12 @@
 package rosbotcollector
 
 import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"testing"
 )
@@ -60,3 +66,169 @@ func Test_parseActivityEndpoint(t *testing.T) {
 		}
 	})
 }
+
+func Test_parseTimezone(t *testing.T) {
+	file, err := os.Open("./samples/account_edit.html")
+	if err != nil {
+		t.Errorf("could not open html file")
+	}
+	defer file.Close()
+
+	test := struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		name:    "timezone is found",
+		want:    "Europe/Paris",
+		wantErr: false,
+	}
+	t.Run(test.name, func(t *testing.T) {
+		got, err := parseTimezone(file)
+		if (err != nil) != test.wantErr {
+			t.Errorf("parseTimezone() error = %v, wantErr %v", err, test.wantErr)
+			return
+		}
+		if got.String() != test.want {
+			t.Errorf("parseTimezone() = %v, want %v", got, test.want)
+		}
+	})
+}
+
+func Test_httpService_getAuthenticated(t *testing.T) {
+	login, err := ioutil.ReadFile("./samples/login.html")
+	if err != nil {
+		t.Fatalf("could not open html file")
+	}
+
+	tests := []struct {
+		name       string
+		password   string
+		wantLogins int
+		wantErr    error
+	}{
+		{
+			name:       "expired session is refreshed",
+			password:   "test",
+			wantLogins: 1,
+		},
+		{
+			name:       "refresh fails",
+			password:   "wrong",
+			wantLogins: 1,
+			wantErr:    ErrCookiesRefresh,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logins := 0
+			mux := http.NewServeMux()
+			mux.HandleFunc("/user/login", func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet {
+					_, _ = w.Write(login)
+					return
+				}
+				logins++
+				_ = r.ParseForm()
+				if r.PostForm.Get("pass") != "test" {
+					_, _ = w.Write(login)
+					return
+				}
+				http.SetCookie(w, &http.Cookie{Name: "SESS", Value: "valid", Path: "/"})
+				http.Redirect(w, r, "/user/testuser", http.StatusFound)
+			})
+			mux.HandleFunc("/user/testuser", func(w http.ResponseWriter, r *http.Request) {})
+			mux.HandleFunc("/user/1234567/bot-activity", func(w http.ResponseWriter, r *http.Request) {
+				// The session has expired until the user logs in again.
+				if c, err := r.Cookie("SESS"); err != nil || c.Value != "valid" {
+					http.Redirect(w, r, "/user/login", http.StatusFound)
+					return
+				}
+				_, _ = w.Write([]byte("activity"))
+			})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			jar, _ := cookiejar.New(nil)
+			s := &httpService{
+				credentials: &credentials{UsernameOrEmail: "test", Password: tt.password},
+				client:      &http.Client{Jar: jar},
+				endpoints: &endpoints{
+					Login:    server.URL + "/user/login",
+					User:     server.URL + "/user/1234567",
+					Activity: server.URL + "/user/1234567/bot-activity",
+				},
+			}
+
+			res, err := s.GetActivity("")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetActivity() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if logins != tt.wantLogins {
+				t.Errorf("GetActivity() logins = %v, want %v", logins, tt.wantLogins)
+			}
+			if err != nil {
+				return
+			}
+			defer res.Body.Close()
+			if body, _ := ioutil.ReadAll(res.Body); string(body) != "activity" {
+				t.Errorf("GetActivity() body = %s", body)
+			}
+		})
+	}
+}
+
+func Test_httpService_GetPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c, err := r.Cookie("SESS"); err != nil || c.Value != "valid" {
+			http.Error(w, "no session", http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(r.URL.RequestURI()))
+	}))
+	defer server.Close()
+
+	jar, _ := cookiejar.New(nil)
+	u, _ := url.Parse(server.URL)
+	jar.SetCookies(u, []*http.Cookie{{Name: "SESS", Value: "valid", Path: "/"}})
+	s := &httpService{client: &http.Client{Jar: jar}, endpoints: &endpoints{Base: server.URL}}
+
+	res, err := s.GetPage("/system/files/profiles/gr90.xml?v=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if body, _ := ioutil.ReadAll(res.Body); string(body) != "/system/files/profiles/gr90.xml?v=2" {
+		t.Errorf("GetPage() body = %s", body)
+	}
+}
+
+func Test_httpService_GetPublicPage(t *testing.T) {
+	posts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			posts++
+		}
+		if r.URL.Path != dropStatisticsPath {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("statistics"))
+	}))
+	defer server.Close()
+
+	// No session; public pages must not trigger a login.
+	s := &httpService{client: server.Client(), endpoints: &endpoints{Base: server.URL, Login: server.URL + loginEndpoint}}
+	res, err := s.GetPublicPage(dropStatisticsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if body, _ := ioutil.ReadAll(res.Body); string(body) != "statistics" || posts != 0 {
+		t.Errorf("GetPublicPage() body = %s, with %d login posts", body, posts)
+	}
+
+	if _, err := s.GetPublicPage("/missing"); !errors.Is(err, ErrUnexpectedStatus) {
+		t.Errorf("GetPublicPage() error = %v, want %v", err, ErrUnexpectedStatus)
+	}
+}
@@ -0,0 +1,72 @@
+// Package cli holds what the commands of the module share: the flags of the account to
+// authenticate as, and the loading of its credentials.
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	rbc "github.com/maxzaleski/go-rosbot-collector"
+)
+
+// Account is the account a command authenticates as.
+type Account struct {
+	// Credentials is the path of a JSON credentials file; the environment is read otherwise.
+	Credentials string
+	// Timezone is the display timezone of the account: empty, an IANA name, or "auto" to read it
+	// from the account.
+	Timezone string
+}
+
+// Credentials are the credentials of an account, as stored by a credentials file.
+type Credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// RegisterFlags adds the '-credentials' and '-timezone' flags to the flag set.
+func (a *Account) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&a.Credentials, "credentials", "", "JSON credentials file; defaults to $ROSBOT_USERNAME and $ROSBOT_PASSWORD")
+	fs.StringVar(&a.Timezone, "timezone", "", `display timezone of the account, i.e. "Europe/Paris", or "auto" to read it from the account`)
+}
+
+// LoadCredentials reads the credentials file, or the ROSBOT_USERNAME and ROSBOT_PASSWORD
+// environment variables when there is none.
+func (a *Account) LoadCredentials(getenv func(string) string) (*Credentials, error) {
+	creds := &Credentials{}
+	if a.Credentials != "" {
+		b, err := ioutil.ReadFile(a.Credentials)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, creds); err != nil {
+			return nil, fmt.Errorf("invalid credentials file: %v", err)
+		}
+	} else {
+		creds.Username, creds.Password = getenv("ROSBOT_USERNAME"), getenv("ROSBOT_PASSWORD")
+	}
+	if creds.Username == "" || creds.Password == "" {
+		return nil, errors.New("missing credentials: set ROSBOT_USERNAME and ROSBOT_PASSWORD, or use -credentials")
+	}
+	return creds, nil
+}
+
+// ClientOptions returns the client options of the account's timezone.
+func (a *Account) ClientOptions() ([]rbc.ClientOption, error) {
+	switch a.Timezone {
+	case "":
+		return nil, nil
+	case "auto":
+		return []rbc.ClientOption{rbc.WithTimezoneDetection()}, nil
+	default:
+		loc, err := time.LoadLocation(a.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		return []rbc.ClientOption{rbc.WithLocation(loc)}, nil
+	}
+}
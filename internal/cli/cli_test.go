@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAccount_LoadCredentials(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "credentials.json")
+	if err := ioutil.WriteFile(valid, []byte(`{"username": "file", "password": "secret"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := ioutil.WriteFile(invalid, []byte(`username=file`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{"ROSBOT_USERNAME": "env", "ROSBOT_PASSWORD": "secret"}
+
+	tests := []struct {
+		name        string
+		credentials string
+		env         map[string]string
+		want        *Credentials
+		wantErr     string
+	}{
+		{name: "file", credentials: valid, env: env, want: &Credentials{Username: "file", Password: "secret"}},
+		{name: "environment", env: env, want: &Credentials{Username: "env", Password: "secret"}},
+		{name: "missing", wantErr: "missing credentials"},
+		{name: "invalid file", credentials: invalid, wantErr: "invalid credentials file"},
+		{name: "no file", credentials: filepath.Join(dir, "none.json"), wantErr: "no such file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Account{Credentials: tt.credentials}
+			got, err := a.LoadCredentials(func(k string) string { return tt.env[k] })
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadCredentials() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadCredentials() = %+v, %v, want %+v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccount_ClientOptions(t *testing.T) {
+	tests := []struct {
+		timezone string
+		want     int
+		wantErr  bool
+	}{
+		{timezone: "", want: 0},
+		{timezone: "auto", want: 1},
+		{timezone: "Europe/Paris", want: 1},
+		{timezone: "Mars/Olympus_Mons", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.timezone, func(t *testing.T) {
+			got, err := (&Account{Timezone: tt.timezone}).ClientOptions()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ClientOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Errorf("ClientOptions() = %d options, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package rosbotcollector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// License is a bot license, as listed by the 'user/{user_id}/bot-licenses' page.
+type License struct {
+	// Key is masked but for its last 4 characters, so that licenses may be logged safely.
+	Key    string `json:"key"`
+	Type   string `json:"type"`
+	Status string `json:"status"`
+	// Expires is zero for licenses which never expire, and for those whose expiry date could not
+	// be parsed.
+	Expires time.Time `json:"expires"`
+	// NeverExpires is true for lifetime licenses, whose expiry date is displayed as "Never" or "-".
+	NeverExpires bool            `json:"never_expires"`
+	RawExpires   string          `json:"raw_expires"`
+	Warnings     []*ParseWarning `json:"warnings,omitempty"`
+}
+
+var (
+	// ErrNeverExpires is returned when computing the days left before a lifetime license expires.
+	ErrNeverExpires = errors.New("license never expires")
+	// ErrUnknownExpiry is returned when computing the days left before a license whose expiry date
+	// could not be parsed expires.
+	ErrUnknownExpiry = errors.New("license expiry date is unknown")
+)
+
+// DaysUntilExpiry returns the whole days left before the license expires, which are negative once
+// it has.
+func (l *License) DaysUntilExpiry(now time.Time) (int, error) {
+	switch {
+	case l.NeverExpires:
+		return 0, ErrNeverExpires
+	case l.Expires.IsZero():
+		return 0, fmt.Errorf("%w: %q", ErrUnknownExpiry, l.RawExpires)
+	}
+	return int(math.Floor(l.Expires.Sub(now).Hours() / 24)), nil
+}
+
+// ExpiringLicenses returns the licenses which have not expired yet, but will within the given
+// duration. Licenses whose expiry date could not be parsed are returned too, as they may.
+func ExpiringLicenses(licenses []*License, now time.Time, within time.Duration) []*License {
+	expiring := make([]*License, 0)
+	for _, l := range licenses {
+		if l.NeverExpires {
+			continue
+		}
+		if l.Expires.IsZero() || (l.Expires.After(now) && !l.Expires.After(now.Add(within))) {
+			expiring = append(expiring, l)
+		}
+	}
+	return expiring
+}
+
+func (c *client) Licenses(ctx context.Context) ([]*License, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	res, err := c.httpService.GetUserPage("bot-licenses")
+	if err != nil {
+		return nil, err
+	}
+	return parseLicenses(res.Body, c.location)
+}
+
+func parseLicenses(body io.ReadCloser, loc *time.Location) ([]*License, error) {
+	doc, err := goquery.NewDocumentFromReader(body)
+	if err != nil {
+		return nil, err
+	}
+	_ = body.Close()
+
+	licenses := make([]*License, 0)
+	eachTableRow(doc.Find("div.view-bot-licenses table"), func(r *tableRow) {
+		l := &License{
+			Key:        maskLicenseKey(r.text("license key", "key")),
+			Type:       r.text("type", "license type"),
+			Status:     r.text("status"),
+			RawExpires: r.text("expires", "expiration", "expiry date"),
+		}
+		// Lifetime licenses have no expiry date.
+		switch strings.ToLower(l.RawExpires) {
+		case "-", "never":
+			l.NeverExpires = true
+		case "":
+			// Precaution; a missing column must not pass for a lifetime license.
+			l.Warnings = append(l.Warnings, &ParseWarning{Field: "expires", Raw: l.RawExpires, Message: "missing expiry date"})
+		default:
+			t, err := parseTimestamp(l.RawExpires, loc)
+			if err != nil {
+				l.Warnings = append(l.Warnings, &ParseWarning{Field: "expires", Raw: l.RawExpires, Message: err.Error()})
+			}
+			l.Expires = t
+		}
+		licenses = append(licenses, l)
+	})
+	return licenses, nil
+}
+
+// maskLicenseKey replaces every letter and digit of the key but the last 4 with '*'; separators
+// are kept.
+func maskLicenseKey(key string) string {
+	masked := []rune(key)
+	visible := 0
+	for i := len(masked) - 1; i >= 0; i-- {
+		r := masked[i]
+		if r == '-' || r == ' ' {
+			continue
+		}
+		if visible < 4 {
+			visible++
+			continue
+		}
+		masked[i] = '*'
+	}
+	return string(masked)
+}
@@ -0,0 +1,119 @@
+package rosbotcollector
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_parseLicenses(t *testing.T) {
+	file, err := os.Open("./samples/bot_licenses.html")
+	if err != nil {
+		t.Fatalf("could not open html file")
+	}
+	defer file.Close()
+
+	paris, _ := time.LoadLocation("Europe/Paris")
+	got, err := parseLicenses(file, paris)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*License{
+		{Key: "***-****-****-M8LA", Type: "30 days", Status: "Active", Expires: time.Date(2019, 10, 12, 18, 30, 0, 0, paris), RawExpires: "12/10/2019 - 18:30"},
+		{Key: "***-****-****-P0WE", Type: "Lifetime", Status: "Active", NeverExpires: true, RawExpires: "Never"},
+		{Key: "***-****-****-ZZ90", Type: "1 day", Status: "Expired", Expires: time.Date(2019, 9, 1, 8, 0, 0, 0, paris), RawExpires: "01/09/2019 - 08:00"},
+		{
+			Key: "***-****-****-Q7RS", Type: "7 days", Status: "Pending", RawExpires: "12 Oct 2019",
+			Warnings: []*ParseWarning{{Field: "expires", Raw: "12 Oct 2019", Message: ErrInvalidTimestamp.Error()}},
+		},
+		{
+			// A blank expiry date is not taken for a lifetime license.
+			Key: "***-****-****-X4YZ", Type: "30 days", Status: "Active",
+			Warnings: []*ParseWarning{{Field: "expires", Message: "missing expiry date"}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		for i := range got {
+			t.Logf("got[%d] = %+v", i, got[i])
+		}
+		t.Errorf("parseLicenses() = %v, want %v", got, want)
+	}
+}
+
+func Test_maskLicenseKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "RB3-7F2K-9QX4-M8LA", want: "***-****-****-M8LA"},
+		{key: "ABCDEFGH", want: "****EFGH"},
+		{key: "****-M8LA", want: "****-M8LA"},
+		{key: "AB1", want: "AB1"},
+		{key: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := maskLicenseKey(tt.key); got != tt.want {
+				t.Errorf("maskLicenseKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLicense_DaysUntilExpiry(t *testing.T) {
+	now := time.Date(2019, 9, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		license *License
+		want    int
+		wantErr error
+	}{
+		{name: "in a week", license: &License{Expires: now.Add(7 * 24 * time.Hour)}, want: 7},
+		{name: "later today", license: &License{Expires: now.Add(2 * time.Hour)}, want: 0},
+		{name: "expired an hour ago", license: &License{Expires: now.Add(-time.Hour)}, want: -1},
+		{name: "never", license: &License{NeverExpires: true, RawExpires: "Never"}, wantErr: ErrNeverExpires},
+		{name: "unparsable", license: &License{RawExpires: "12 Oct 2019"}, wantErr: ErrUnknownExpiry},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.license.DaysUntilExpiry(now)
+			if got != tt.want || !errors.Is(err, tt.wantErr) {
+				t.Errorf("DaysUntilExpiry() = %d, %v, want %d, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_ExpiringLicenses(t *testing.T) {
+	now := time.Date(2019, 9, 10, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	soon := &License{Key: "soon", Expires: now.Add(2 * day)}
+	later := &License{Key: "later", Expires: now.Add(30 * day)}
+	expired := &License{Key: "expired", Expires: now.Add(-day)}
+	lifetime := &License{Key: "lifetime", NeverExpires: true}
+	unknown := &License{Key: "unknown", RawExpires: "12 Oct 2019"}
+
+	got := ExpiringLicenses([]*License{soon, later, expired, lifetime, unknown}, now, 3*day)
+	if want := []*License{soon, unknown}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ExpiringLicenses() = %v, want %v", got, want)
+	}
+}
+
+func TestClient_Licenses(t *testing.T) {
+	s := &fakeHTTPService{pages: map[string]string{"bot-licenses": "./samples/bot_licenses.html"}}
+	c := &client{httpService: s, location: time.UTC}
+
+	got, err := c.Licenses(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 5 || got[0].Expires != time.Date(2019, 10, 12, 18, 30, 0, 0, time.UTC) {
+		t.Errorf("Licenses() = %v", got)
+	}
+	if want := []string{"bot-licenses"}; !reflect.DeepEqual(s.segments, want) {
+		t.Errorf("requested segments = %v, want %v", s.segments, want)
+	}
+}
@@ -0,0 +1,242 @@
+// Package metrics exports the health of the scraping, and the collected loot, as Prometheus
+// metrics.
+//
+//	e := metrics.NewExporter()
+//	c, err := rosbotcollector.NewClient(user, password, rosbotcollector.WithTransport(e.RoundTripper(nil)))
+//	collector := rosbotcollector.NewCollector(e.Client(c), nil)
+//	collector.Handle(e.Observe)
+//	http.Handle("/metrics", e.Handler())
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	rbc "github.com/maxzaleski/go-rosbot-collector"
+)
+
+const namespace = "rosbot"
+
+// Exporter holds the metrics. Its collectors are registered in its own registry, served by
+// `Handler`; they can also be registered elsewhere through `Collectors`.
+type Exporter struct {
+	registry *prometheus.Registry
+
+	requestDuration *prometheus.HistogramVec
+	requests        *prometheus.CounterVec
+	loginAttempts   prometheus.Counter
+	scrapes         *prometheus.CounterVec
+	errors          *prometheus.CounterVec
+	parseWarnings   *prometheus.CounterVec
+	lastSuccess     prometheus.Gauge
+	updates         prometheus.Counter
+	items           *prometheus.CounterVec
+}
+
+// NewExporter returns a new instance of `metrics.Exporter`.
+func NewExporter() *Exporter {
+	e := &Exporter{
+		registry: prometheus.NewRegistry(),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Duration of the requests made to the site.",
+			Buckets:   []float64{.1, .25, .5, 1, 2.5, 5, 10},
+		}, []string{"page"}),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "Requests made to the site, by page and status code; 'error' when no response was received.",
+		}, []string{"page", "code"}),
+		loginAttempts: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "login_attempts_total",
+			Help:      "Login forms posted, on authentication and session refresh.",
+		}),
+		scrapes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "scrapes_total",
+			Help:      "Activity pages parsed, by result.",
+		}, []string{"result"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "scrape_errors_total",
+			Help:      "Failed scrapes, by kind of error.",
+		}, []string{"kind"}),
+		parseWarnings: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "parse_warnings_total",
+			Help:      "Server update fields which could not be parsed, by field.",
+		}, []string{"field"}),
+		lastSuccess: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "last_success_timestamp_seconds",
+			Help:      "Unix time of the last successful scrape.",
+		}),
+		updates: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "updates_total",
+			Help:      "Server updates collected.",
+		}),
+		items: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "items_total",
+			Help:      "Legendary items collected, by rarity, quality and destination.",
+		}, []string{"rarity", "quality", "destination"}),
+	}
+	e.registry.MustRegister(e.Collectors()...)
+	return e
+}
+
+// Collectors returns the collectors of the exporter, i.e. to register them in another registry.
+func (e *Exporter) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		e.requestDuration, e.requests, e.loginAttempts, e.scrapes, e.errors, e.parseWarnings,
+		e.lastSuccess, e.updates, e.items,
+	}
+}
+
+// Registry returns the registry of the exporter, i.e. to register additional collectors.
+func (e *Exporter) Registry() *prometheus.Registry {
+	return e.registry
+}
+
+// Handler serves the metrics of the exporter's registry, i.e. on '/metrics'.
+func (e *Exporter) Handler() http.Handler {
+	return promhttp.HandlerFor(e.registry, promhttp.HandlerOpts{Registry: e.registry})
+}
+
+// RoundTripper instruments the requests made to the site; pass it to `rosbotcollector.WithTransport`.
+// `http.DefaultTransport` is used when `next` is nil.
+func (e *Exporter) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		page := pageOf(req)
+		if page == "login" && req.Method == http.MethodPost {
+			e.loginAttempts.Inc()
+		}
+
+		start := time.Now()
+		res, err := next.RoundTrip(req)
+		e.requestDuration.WithLabelValues(page).Observe(time.Since(start).Seconds())
+		code := "error"
+		if err == nil {
+			code = strconv.Itoa(res.StatusCode)
+		}
+		e.requests.WithLabelValues(page, code).Inc()
+		return res, err
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// pageOf labels the request by page rather than URL, which holds the user ID; i.e. "login",
+// "activity" or the segment of 'user/{user_id}/{segment}'.
+func pageOf(req *http.Request) string {
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	switch {
+	case len(parts) == 2 && parts[0] == "user" && parts[1] == "login":
+		return "login"
+	case len(parts) == 3 && parts[0] == "user" && parts[2] == "bot-activity":
+		return "activity"
+	case len(parts) >= 3 && parts[0] == "user":
+		return parts[2]
+	case len(parts) == 2 && parts[0] == "user":
+		return "user"
+	default:
+		return "other"
+	}
+}
+
+// Client instruments the scrapes of the client: results, errors, parse warnings and the time of
+// the last success.
+func (e *Exporter) Client(c rbc.Client) rbc.Client {
+	return &client{Client: c, e: e}
+}
+
+type client struct {
+	rbc.Client
+	e *Exporter
+}
+
+func (c *client) ParseWithDefaults(ctx context.Context) ([]*rbc.ServerUpdate, error) {
+	updates, err := c.Client.ParseWithDefaults(ctx)
+	c.e.scraped(updates, err)
+	return updates, err
+}
+
+func (c *client) ParseWithConfig(ctx context.Context, config *rbc.ParserConfig) ([]*rbc.ServerUpdate, error) {
+	updates, err := c.Client.ParseWithConfig(ctx, config)
+	c.e.scraped(updates, err)
+	return updates, err
+}
+
+func (c *client) ParsePageWithConfig(ctx context.Context, config *rbc.ParserConfig) (*rbc.ActivityPage, error) {
+	page, err := c.Client.ParsePageWithConfig(ctx, config)
+	var updates []*rbc.ServerUpdate
+	if page != nil {
+		updates = page.Updates
+	}
+	c.e.scraped(updates, err)
+	return page, err
+}
+
+func (e *Exporter) scraped(updates []*rbc.ServerUpdate, err error) {
+	if err != nil {
+		e.scrapes.WithLabelValues("error").Inc()
+		e.errors.WithLabelValues(errorKind(err)).Inc()
+		return
+	}
+	e.scrapes.WithLabelValues("success").Inc()
+	e.lastSuccess.SetToCurrentTime()
+	for _, u := range updates {
+		for _, w := range u.Warnings {
+			e.parseWarnings.WithLabelValues(w.Field).Inc()
+		}
+	}
+}
+
+// errorKind labels the error by its sentinel, rather than its message.
+func errorKind(err error) string {
+	kinds := []struct {
+		err  error
+		kind string
+	}{
+		{rbc.ErrBadCredentials, "bad_credentials"},
+		{rbc.ErrCookiesRefresh, "cookies_refresh"},
+		{rbc.ErrUnexpectedStatus, "unexpected_status"},
+		{rbc.ErrNoFormBuildID, "parse"},
+		{rbc.ErrNoActivityEndpoint, "parse"},
+		{context.Canceled, "canceled"},
+		{context.DeadlineExceeded, "timeout"},
+	}
+	for _, k := range kinds {
+		if errors.Is(err, k.err) {
+			return k.kind
+		}
+	}
+	return "other"
+}
+
+// Observe counts the server update and its items. It satisfies `rosbotcollector.UpdateHandler`;
+// register it on a collector so that every update is counted once.
+func (e *Exporter) Observe(_ context.Context, u *rbc.ServerUpdate) error {
+	e.updates.Inc()
+	for _, item := range u.Items {
+		e.items.WithLabelValues(string(item.Rarity), string(item.Quality), string(item.Destination)).Inc()
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	rbc "github.com/maxzaleski/go-rosbot-collector"
+)
+
+// scrape returns the text exposition of the exporter's metrics.
+func scrape(t *testing.T, e *Exporter) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	e.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	b, err := ioutil.ReadAll(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func assertContains(t *testing.T, got string, want ...string) {
+	t.Helper()
+	for _, w := range want {
+		if !strings.Contains(got, w) {
+			t.Errorf("metrics do not contain %q:\n%s", w, got)
+		}
+	}
+}
+
+func TestExporter_RoundTripper(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "orders") {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	e := NewExporter()
+	c := &http.Client{Transport: e.RoundTripper(nil)}
+	requests := []struct{ method, path string }{
+		{http.MethodGet, "/user/login"},
+		{http.MethodPost, "/user/login"},
+		{http.MethodGet, "/user/1234567/bot-activity?page=1"},
+		{http.MethodGet, "/user/1234567/orders"},
+	}
+	for _, r := range requests {
+		req, _ := http.NewRequest(r.method, srv.URL+r.path, nil)
+		res, err := c.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = res.Body.Close()
+	}
+
+	assertContains(t, scrape(t, e),
+		`rosbot_requests_total{code="200",page="login"} 2`,
+		`rosbot_requests_total{code="200",page="activity"} 1`,
+		`rosbot_requests_total{code="502",page="orders"} 1`,
+		`rosbot_request_duration_seconds_count{page="activity"} 1`,
+		`rosbot_login_attempts_total 1`,
+	)
+}
+
+type fakeClient struct {
+	rbc.Client
+	updates []*rbc.ServerUpdate
+	err     error
+}
+
+func (c *fakeClient) ParseWithConfig(context.Context, *rbc.ParserConfig) ([]*rbc.ServerUpdate, error) {
+	return c.updates, c.err
+}
+
+func TestExporter_Client(t *testing.T) {
+	e := NewExporter()
+	ctx := context.Background()
+
+	ok := &fakeClient{updates: []*rbc.ServerUpdate{{Warnings: []*rbc.ParseWarning{{Field: "server_timestamp"}}}}}
+	if _, err := e.Client(ok).ParseWithConfig(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	failing := &fakeClient{err: fmt.Errorf("%w: 503 Service Unavailable", rbc.ErrUnexpectedStatus)}
+	if _, err := e.Client(failing).ParseWithConfig(ctx, nil); err == nil {
+		t.Fatal("the error was not returned")
+	}
+	if _, err := e.Client(&fakeClient{err: errors.New("boom")}).ParseWithConfig(ctx, nil); err == nil {
+		t.Fatal("the error was not returned")
+	}
+
+	got := scrape(t, e)
+	assertContains(t, got,
+		`rosbot_scrapes_total{result="success"} 1`,
+		`rosbot_scrapes_total{result="error"} 2`,
+		`rosbot_scrape_errors_total{kind="unexpected_status"} 1`,
+		`rosbot_scrape_errors_total{kind="other"} 1`,
+		`rosbot_parse_warnings_total{field="server_timestamp"} 1`,
+	)
+	if strings.Contains(got, "rosbot_last_success_timestamp_seconds 0\n") {
+		t.Error("the last success time was not set")
+	}
+}
+
+func TestExporter_Observe(t *testing.T) {
+	e := NewExporter()
+	u := &rbc.ServerUpdate{Items: []*rbc.LegendaryItem{
+		{Rarity: rbc.RarityAncient, Quality: rbc.QualityNormal, Destination: rbc.DestinationStashed},
+		{Rarity: rbc.RarityAncient, Quality: rbc.QualityNormal, Destination: rbc.DestinationStashed},
+		{Rarity: rbc.RarityPrimal, Quality: rbc.QualitySet, Destination: rbc.DestinationSalvaged},
+	}}
+	if err := e.Observe(context.Background(), u); err != nil {
+		t.Fatal(err)
+	}
+
+	assertContains(t, scrape(t, e),
+		`rosbot_updates_total 1`,
+		`rosbot_items_total{destination="STASHED",quality="NORMAL",rarity="ANCIENT"} 2`,
+		`rosbot_items_total{destination="SALVAGED",quality="SET",rarity="PRIMAL"} 1`,
+	)
+}
+
+func Test_pageOf(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "https://www.ros-bot.com/user/login", want: "login"},
+		{url: "https://www.ros-bot.com/user/1234567/bot-activity?page=2", want: "activity"},
+		{url: "https://www.ros-bot.com/user/1234567/edit", want: "edit"},
+		{url: "https://www.ros-bot.com/user/1234567", want: "user"},
+		{url: "https://www.ros-bot.com/items-drop-statistics", want: "other"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+			if got := pageOf(req); got != tt.want {
+				t.Errorf("pageOf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package rosbotcollector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// NDJSONEncoder writes server updates as newline-delimited JSON (JSON Lines); one value per line.
+type NDJSONEncoder struct {
+	// Flatten writes an `ItemRecord` per item rather than a `ServerUpdate` per line.
+	Flatten bool
+
+	enc *json.Encoder
+}
+
+// NewNDJSONEncoder returns a new instance of `rosbotcollector.NDJSONEncoder`.
+func NewNDJSONEncoder(w io.Writer) *NDJSONEncoder {
+	enc := json.NewEncoder(w)
+	// Item stats contain '<' and '&' far more often than they end up in HTML.
+	enc.SetEscapeHTML(false)
+	return &NDJSONEncoder{enc: enc}
+}
+
+// Encode writes the server update; or a line per item when flattening.
+// Every line is written as soon as it is encoded, so that pages can be streamed as they are parsed.
+func (e *NDJSONEncoder) Encode(u *ServerUpdate) error {
+	if !e.Flatten {
+		return e.enc.Encode(u)
+	}
+	for _, r := range FlattenUpdates([]*ServerUpdate{u}) {
+		if err := e.enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EncodeAll writes every server update, in order.
+func (e *NDJSONEncoder) EncodeAll(updates []*ServerUpdate) error {
+	for _, u := range updates {
+		if err := e.Encode(u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handle writes the server update. It satisfies `UpdateHandler`.
+func (e *NDJSONEncoder) Handle(_ context.Context, u *ServerUpdate) error {
+	return e.Encode(u)
+}
+
+// NDJSONDecoder reads server updates, or item records, from newline-delimited JSON.
+// Blank lines are skipped.
+type NDJSONDecoder struct {
+	r    *bufio.Reader
+	line int
+}
+
+// NewNDJSONDecoder returns a new instance of `rosbotcollector.NDJSONDecoder`.
+func NewNDJSONDecoder(r io.Reader) *NDJSONDecoder {
+	return &NDJSONDecoder{r: bufio.NewReader(r)}
+}
+
+// DecodeUpdate reads the next line as a server update. `io.EOF` is returned once every line has
+// been read.
+func (d *NDJSONDecoder) DecodeUpdate() (*ServerUpdate, error) {
+	u := new(ServerUpdate)
+	if err := d.decode(u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// DecodeItem reads the next line as an item record. `io.EOF` is returned once every line has
+// been read.
+func (d *NDJSONDecoder) DecodeItem() (*ItemRecord, error) {
+	r := &ItemRecord{LegendaryItem: new(LegendaryItem)}
+	if err := d.decode(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (d *NDJSONDecoder) decode(v interface{}) error {
+	for {
+		b, err := d.r.ReadBytes('\n')
+		if len(b) == 0 && err != nil {
+			return err
+		}
+		d.line++
+		if b = bytes.TrimSpace(b); len(b) == 0 {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if err := json.Unmarshal(b, v); err != nil {
+			return fmt.Errorf("line %d: %w", d.line, err)
+		}
+		return nil
+	}
+}
+
+// ReadUpdates reads every server update of newline-delimited JSON.
+func ReadUpdates(r io.Reader) ([]*ServerUpdate, error) {
+	d := NewNDJSONDecoder(r)
+	var updates []*ServerUpdate
+	for {
+		u, err := d.DecodeUpdate()
+		if err == io.EOF {
+			return updates, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		updates = append(updates, u)
+	}
+}
+
+// ReadItems reads every item record of newline-delimited JSON.
+func ReadItems(r io.Reader) ([]*ItemRecord, error) {
+	d := NewNDJSONDecoder(r)
+	var records []*ItemRecord
+	for {
+		rec, err := d.DecodeItem()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+}
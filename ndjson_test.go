@@ -0,0 +1,109 @@
+package rosbotcollector
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNDJSONEncoder_Encode(t *testing.T) {
+	tests := []struct {
+		name    string
+		flatten bool
+		want    []string
+	}{
+		{
+			name: "updates",
+			want: []string{`{"id":"u1",`, `{"id":"u2",`},
+		},
+		{
+			name:    "items",
+			flatten: true,
+			want:    []string{`{"update_id":"u1","server_timestamp":"2020-05-01T12:00:00Z","id":"u1-0",`, `{"update_id":"u1",`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			e := NewNDJSONEncoder(&b)
+			e.Flatten = tt.flatten
+			if err := e.EncodeAll(csvUpdates()); err != nil {
+				t.Fatal(err)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+			if len(lines) != len(tt.want) {
+				t.Fatalf("Encode() wrote %d lines, want %d:\n%s", len(lines), len(tt.want), b.String())
+			}
+			for i, w := range tt.want {
+				if !strings.HasPrefix(lines[i], w) {
+					t.Errorf("line %d = %s, want prefix %s", i+1, lines[i], w)
+				}
+			}
+		})
+	}
+}
+
+func Test_ReadUpdates(t *testing.T) {
+	var b bytes.Buffer
+	if err := NewNDJSONEncoder(&b).EncodeAll(csvUpdates()); err != nil {
+		t.Fatal(err)
+	}
+	b.WriteString("\n  \n")
+
+	got, err := ReadUpdates(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := csvUpdates(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadUpdates() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_ReadItems(t *testing.T) {
+	var b bytes.Buffer
+	e := NewNDJSONEncoder(&b)
+	e.Flatten = true
+	if err := e.EncodeAll(csvUpdates()); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadItems(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := FlattenUpdates(csvUpdates()); !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadItems() = %+v, want %+v", got, want)
+	}
+}
+
+func TestNDJSONDecoder_DecodeUpdate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    int
+		wantErr string
+	}{
+		{name: "empty", input: ""},
+		{name: "no trailing newline", input: `{"id":"a"}` + "\n" + `{"id":"b"}`, want: 2},
+		{name: "invalid line", input: `{"id":"a"}` + "\n\n" + `{"id":`, wantErr: "line 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadUpdates(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ReadUpdates() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != tt.want {
+				t.Errorf("ReadUpdates() returned %d updates, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
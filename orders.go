@@ -0,0 +1,141 @@
+package rosbotcollector
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Order is an order of the account, as listed by the 'user/{user_id}/orders' page.
+type Order struct {
+	ID      string    `json:"id"`
+	Date    time.Time `json:"date"`
+	RawDate string    `json:"raw_date"`
+	Product string    `json:"product"`
+	// Amount is the total of the order, in cents of `Currency`; negative for refunds.
+	Amount    int64  `json:"amount"`
+	Currency  string `json:"currency"`
+	RawAmount string `json:"raw_amount"`
+	Status    string `json:"status"`
+	// URL is the absolute URL of the order's page on the site.
+	URL      string          `json:"url"`
+	Warnings []*ParseWarning `json:"warnings,omitempty"`
+}
+
+// OrderPage is a single page of the 'user/{user_id}/orders' page, newest orders first.
+type OrderPage struct {
+	Orders []*Order  `json:"orders"`
+	Info   *PageInfo `json:"page_info"`
+}
+
+func (c *client) Orders(ctx context.Context, page PageNumber) (*OrderPage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if page < FirstPage {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidPage, page)
+	}
+	res, err := c.httpService.GetUserPage(fmt.Sprintf("orders?page=%d", page.Index()))
+	if err != nil {
+		return nil, err
+	}
+	return parseOrders(res.Body, c.location)
+}
+
+// AllOrders returns the orders of every page, newest first, through the session of the client.
+func AllOrders(ctx context.Context, c Client) ([]*Order, error) {
+	orders := make([]*Order, 0)
+	for page := FirstPage; ; {
+		p, err := c.Orders(ctx, page)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, p.Orders...)
+
+		next, ok := p.Info.NextPage()
+		// Precaution; a page which does not advance would loop forever.
+		if !ok || next <= page {
+			return orders, nil
+		}
+		page = next
+	}
+}
+
+func parseOrders(body io.ReadCloser, loc *time.Location) (*OrderPage, error) {
+	doc, err := goquery.NewDocumentFromReader(body)
+	if err != nil {
+		return nil, err
+	}
+	_ = body.Close()
+
+	/*
+		<td><a href="https://www.ros-bot.com/user/1234567/orders/4521">4521</a></td>
+		<td>05/09/2019 - 10:12</td>
+		<td>1,000 coins</td>
+		<td>€9.99</td>
+		<td>Pending</td>
+	*/
+	orders := make([]*Order, 0)
+	eachTableRow(doc.Find("div.view-content table"), func(r *tableRow) {
+		id := r.cell("order number", "order id", "order")
+		o := &Order{
+			ID:        strings.TrimPrefix(cleanText(id.Text()), "#"),
+			RawDate:   r.text("created date", "date", "created"),
+			Product:   r.text("products", "product"),
+			RawAmount: r.text("total", "amount"),
+			Status:    r.text("status", "order status"),
+		}
+		href, _ := id.Find("a").Attr("href")
+		o.URL = absoluteURL(href)
+
+		var err error
+		if o.Amount, o.Currency, err = parseMoney(o.RawAmount); err != nil {
+			o.Warnings = append(o.Warnings, &ParseWarning{Field: "amount", Raw: o.RawAmount, Message: err.Error()})
+		}
+		if o.Date, err = parseTimestamp(o.RawDate, loc); err != nil {
+			o.Warnings = append(o.Warnings, &ParseWarning{Field: "date", Raw: o.RawDate, Message: err.Error()})
+		}
+		orders = append(orders, o)
+	})
+	return &OrderPage{Orders: orders, Info: parsePageInfo(doc.Selection)}, nil
+}
+
+var moneyRegex = regexp.MustCompile(`\d(?:[\d.,]*\d)?`)
+
+// parseMoney parses an amount of money, i.e. "€1,024.50", "9,99 €" or "-USD 2.00", into signed
+// cents and the currency, as displayed. The last '.' or ',' is the decimal separator when 1 or 2
+// digits follow it; every other one separates thousands.
+func parseMoney(raw string) (int64, string, error) {
+	m := moneyRegex.FindStringIndex(raw)
+	if m == nil {
+		return 0, "", fmt.Errorf("invalid amount of money %q", raw)
+	}
+	number := raw[m[0]:m[1]]
+
+	units, fraction := number, ""
+	if i := strings.LastIndexAny(number, ".,"); i >= 0 && len(number)-i-1 <= 2 {
+		units, fraction = number[:i], number[i+1:]
+	}
+	n, err := strconv.ParseInt(strings.NewReplacer(",", "", ".", "").Replace(units), 10, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid amount of money %q", raw)
+	}
+	// "2.5" is 2 units and 50 cents.
+	cents, _ := strconv.ParseInt((fraction + "00")[:2], 10, 64)
+	amount := n*100 + cents
+
+	// The sign may precede the currency, i.e. "-€9.99".
+	prefix := raw[:m[0]]
+	if strings.Contains(prefix, "-") {
+		amount = -amount
+		prefix = strings.Replace(prefix, "-", "", 1)
+	}
+	currency := strings.TrimSpace(prefix + raw[m[1]:])
+	return amount, currency, nil
+}
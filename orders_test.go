@@ -0,0 +1,112 @@
+package rosbotcollector
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_parseOrders(t *testing.T) {
+	file, err := os.Open("./samples/orders.html")
+	if err != nil {
+		t.Fatalf("could not open html file")
+	}
+	defer file.Close()
+
+	paris, _ := time.LoadLocation("Europe/Paris")
+	got, err := parseOrders(file, paris)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Order{
+		{
+			ID: "4521", Date: time.Date(2019, 9, 5, 10, 12, 0, 0, paris), RawDate: "05/09/2019 - 10:12",
+			Product: "1,000 coins", Amount: 999, Currency: "€", RawAmount: "€9.99", Status: "Pending",
+			URL: "https://www.ros-bot.com/user/1234567/orders/4521",
+		},
+		{
+			ID: "4410", Date: time.Date(2019, 9, 1, 21, 58, 0, 0, paris), RawDate: "01/09/2019 - 21:58",
+			Product: "Bot license: 30 days", Amount: 102450, Currency: "€", RawAmount: "€1,024.50", Status: "Completed",
+			URL: "https://www.ros-bot.com/user/1234567/orders/4410",
+		},
+	}
+	if !reflect.DeepEqual(got.Orders, want) {
+		for i := range got.Orders {
+			t.Logf("got[%d] = %+v", i, got.Orders[i])
+		}
+		t.Errorf("parseOrders() = %v, want %v", got.Orders, want)
+	}
+	if got.Info.CurrentPage != FirstPage || got.Info.TotalPages != 2 || !got.Info.HasNext || got.Info.TotalEntries != 3 {
+		t.Errorf("parseOrders() info = %+v", got.Info)
+	}
+}
+
+func Test_parseMoney(t *testing.T) {
+	tests := []struct {
+		raw          string
+		want         int64
+		wantCurrency string
+		wantErr      bool
+	}{
+		{raw: "€9.99", want: 999, wantCurrency: "€"},
+		{raw: "€1,024.50", want: 102450, wantCurrency: "€"},
+		{raw: "USD 2.00", want: 200, wantCurrency: "USD"},
+		{raw: "$2.5", want: 250, wantCurrency: "$"},
+		{raw: "15 €", want: 1500, wantCurrency: "€"},
+		{raw: "-€9.99", want: -999, wantCurrency: "€"},
+		{raw: "9,99 €", want: 999, wantCurrency: "€"},
+		{raw: "1.024,50 €", want: 102450, wantCurrency: "€"},
+		{raw: "€1,000", want: 100000, wantCurrency: "€"},
+		{raw: "free", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, currency, err := parseMoney(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseMoney() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want || currency != tt.wantCurrency {
+				t.Errorf("parseMoney() = %d, %q, want %d, %q", got, currency, tt.want, tt.wantCurrency)
+			}
+		})
+	}
+}
+
+func TestClient_Orders(t *testing.T) {
+	s := &fakeHTTPService{pages: map[string]string{
+		"orders?page=0": "./samples/orders.html",
+		"orders?page=1": "./samples/orders_2.html",
+	}}
+	c := &client{httpService: s, location: time.UTC}
+	ctx := context.Background()
+
+	page, err := c.Orders(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Orders) != 1 || page.Orders[0].Status != "Canceled" || page.Info.CurrentPage != 2 || page.Info.HasNext {
+		t.Errorf("Orders() = %+v, %+v", page.Orders, page.Info)
+	}
+	if _, err := c.Orders(ctx, 0); !errors.Is(err, ErrInvalidPage) {
+		t.Errorf("Orders() error = %v, want %v", err, ErrInvalidPage)
+	}
+
+	s.segments = nil
+	all, err := AllOrders(ctx, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, o := range all {
+		ids = append(ids, o.ID)
+	}
+	if want := []string{"4521", "4410", "3977"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("AllOrders() = %v, want %v", ids, want)
+	}
+	if want := []string{"orders?page=0", "orders?page=1"}; !reflect.DeepEqual(s.segments, want) {
+		t.Errorf("requested segments = %v, want %v", s.segments, want)
+	}
+}
@@ -2,9 +2,14 @@ package rosbotcollector
 
 import (
 	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"net/http"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -16,96 +21,334 @@ type (
 	Parser interface {
 		// Parse parses server updates from the '/bot-activity' page.
 		Parse(ctx context.Context) ([]*ServerUpdate, error)
+		// ParsePage parses server updates, alongside the pagination metadata, from the
+		// '/bot-activity' page.
+		ParsePage(ctx context.Context) (*ActivityPage, error)
 	}
 
 	parser struct {
 		config      *ParserConfig
 		httpService HTTPService
+		location    *time.Location
 	}
 )
 
-func newParser(c *ParserConfig, s HTTPService) Parser {
+func newParser(c *ParserConfig, s HTTPService, fallback *time.Location) Parser {
+	// The configuration's location takes precedence over the client's.
+	loc := c.Location
+	if loc == nil {
+		loc = fallback
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
 	return &parser{
 		config:      c,
 		httpService: s,
+		location:    loc,
 	}
 }
 
 func (p *parser) Parse(ctx context.Context) ([]*ServerUpdate, error) {
-	body, err := p.httpService.GetActivity(assignSearchParams(p.config))
+	page, err := p.ParsePage(ctx)
 	if err != nil {
 		return nil, err
 	}
-	defer body.Close()
+	return page.Updates, nil
+}
 
-	doc, err := goquery.NewDocumentFromReader(body)
+func (p *parser) ParsePage(ctx context.Context) (*ActivityPage, error) {
+	searchSegment, err := assignSearchParams(p.config)
+	if err != nil {
+		return nil, err
+	}
+	res, err := p.httpService.GetActivity(searchSegment)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	// Relative timestamps ("9 hours 27 min ago") are computed against the server's clock.
+	date, err := http.ParseTime(res.Header.Get("Date"))
+	if err != nil {
+		date = time.Now()
+	}
+
+	doc, err := goquery.NewDocumentFromReader(res.Body)
 	if err != nil {
 		return nil, err
 	}
 	rawUpdates := doc.Find("div.timeline-item")
 
 	// Every server update is parsed concurrently.
-	// For every update (u • typically 2-4) there are u * 3 go routines spawned which
-	// concurrently parse the legendary rawUpdates.
-	updateChan := make(chan *ServerUpdate, rawUpdates.Length())
+	// For every update (u • typically 2-4) there are u * itemWorkers go routines spawned which
+	// concurrently parse its legendary items.
+	updateChan := make(chan *parsedUpdate, rawUpdates.Length())
 	wg := &sync.WaitGroup{}
 	wg.Add(rawUpdates.Length())
 
-	rawUpdates.Each(func(_ int, s *goquery.Selection) {
-		go parseUpdate(ctx, wg, updateChan, s, p.config)
+	rawUpdates.Each(func(i int, s *goquery.Selection) {
+		go parseUpdate(ctx, wg, updateChan, i, s, p.location, date)
 	})
 
 	wg.Wait()
 	close(updateChan)
 
-	parsedUpdates := make([]*ServerUpdate, 0, rawUpdates.Length())
-	for u := range updateChan {
-		parsedUpdates = append(parsedUpdates, u)
+	parsedUpdates := make([]*ServerUpdate, rawUpdates.Length())
+	for pu := range updateChan {
+		parsedUpdates[pu.position] = pu.update
+	}
+
+	// Identifiers are assigned page-wide, before any of the client-side filtering.
+	assignIDs(parsedUpdates)
+	for _, u := range parsedUpdates {
+		filterUpdate(u, p.config)
 	}
 
-	// Since every update is parsed concurrently, entries may not be sorted.
-	sort.Slice(parsedUpdates, func(i, j int) bool {
-		return parsedUpdates[i].ServerTimestamp.Before(parsedUpdates[j].ServerTimestamp)
+	sortOldestFirst(parsedUpdates)
+	return &ActivityPage{
+		Updates: parsedUpdates,
+		Info:    parsePageInfo(doc.Selection),
+	}, nil
+}
+
+var (
+	pageHeaderRegex = regexp.MustCompile(`Displaying\s+(\d+)\s*-\s*(\d+)\s+of\s+(\d+)`)
+	pageQueryRegex  = regexp.MustCompile(`[?&]page=(\d+)`)
+)
+
+func parsePageInfo(s *goquery.Selection) *PageInfo {
+	/*
+		Example of the pagination metadata
+
+		<div class="view-header">
+			Displaying 1 - 50 of 8072
+		</div>
+		...
+		<ul class="pagination">
+			<li class="active"><span>1</span></li>
+			<li><a title="Go to page 2" href="https://www.ros-bot.com/user/1234567/bot-activity?page=1">2</a></li>
+			...
+			<li class="next"><a title="Go to next page" href="...?page=1">next ›</a></li>
+			<li class="pager-last"><a title="Go to last page" href="...?page=161">last »</a></li>
+		</ul>
+	*/
+	info := &PageInfo{CurrentPage: FirstPage}
+
+	// The header is absent whenever there are no entries to display.
+	m := pageHeaderRegex.FindStringSubmatch(s.Find("div.view-header").Text())
+	if m == nil {
+		return info
+	}
+	info.FirstEntry, _ = strconv.Atoi(m[1])
+	info.LastEntry, _ = strconv.Atoi(m[2])
+	info.TotalEntries, _ = strconv.Atoi(m[3])
+
+	pager := s.Find("ul.pagination")
+	if n, err := strconv.Atoi(strings.TrimSpace(pager.Find("li.active").First().Text())); err == nil {
+		info.CurrentPage = PageNumber(n)
+	}
+	info.HasNext = pager.Find("li.next, li.pager-next").Length() > 0
+	info.HasPrevious = pager.Find("li.prev, li.previous, li.pager-previous").Length() > 0
+
+	// The last page may hold fewer entries than the others.
+	info.PageSize = info.LastEntry - info.FirstEntry + 1
+	if !info.HasNext && info.CurrentPage > FirstPage {
+		info.PageSize = (info.FirstEntry - 1) / info.CurrentPage.Index()
+	}
+
+	if info.PageSize > 0 {
+		info.TotalPages = (info.TotalEntries + info.PageSize - 1) / info.PageSize
+	}
+	// Precaution; the '?page=' parameter of the last page link is zero-based.
+	href, _ := pager.Find("li.pager-last a").Attr("href")
+	if m := pageQueryRegex.FindStringSubmatch(href); m != nil {
+		if n, err := strconv.Atoi(m[1]); err == nil && int(PageFromIndex(n)) > info.TotalPages {
+			info.TotalPages = int(PageFromIndex(n))
+		}
+	}
+	if info.TotalPages < int(info.CurrentPage) {
+		info.TotalPages = int(info.CurrentPage)
+	}
+	return info
+}
+
+// sortOldestFirst sorts the server updates of a page, listed newest first, by timestamp. Updates of
+// the same minute keep their page order, reversed, whatever the order they were parsed in.
+func sortOldestFirst(updates []*ServerUpdate) {
+	for i, j := 0, len(updates)-1; i < j; i, j = i+1, j-1 {
+		updates[i], updates[j] = updates[j], updates[i]
+	}
+	sort.SliceStable(updates, func(i, j int) bool {
+		return updates[i].ServerTimestamp.Before(updates[j].ServerTimestamp)
 	})
-	return parsedUpdates, nil
 }
 
+// parsedUpdate is a server update, alongside its position on the page.
+type parsedUpdate struct {
+	// position is the position of the update on the page; newest first.
+	position int
+	update   *ServerUpdate
+}
+
+// parseUpdate parses a server update and every one of its items; neither identifiers nor
+// filtering are applied.
 func parseUpdate(
 	ctx context.Context,
 	wg *sync.WaitGroup,
-	out chan<- *ServerUpdate,
+	out chan<- *parsedUpdate,
+	position int,
 	s *goquery.Selection,
-	config *ParserConfig,
+	loc *time.Location,
+	date time.Time,
 ) {
 	defer wg.Done()
 
 	items := s.Find("p.m-b-xs")
 	itemsChan := make(chan *LegendaryItem, items.Length())
 
-	jobs := make(chan *goquery.Selection)
-	for i := 0; i <= 3; i++ {
-		go parseLegendaryItemWorker(ctx, jobs, itemsChan)
+	jobs := make(chan *itemJob)
+	workers := &sync.WaitGroup{}
+	workers.Add(itemWorkers)
+	for i := 0; i < itemWorkers; i++ {
+		go parseLegendaryItemWorker(ctx, workers, jobs, itemsChan)
 	}
-	items.Each(func(_ int, s *goquery.Selection) { jobs <- s })
+	items.Each(func(i int, s *goquery.Selection) { jobs <- &itemJob{index: i, s: s} })
 	close(jobs)
 
+	// Every worker must be done before the channel can be drained.
+	workers.Wait()
+	close(itemsChan)
+
 	legendaryItems := make([]*LegendaryItem, 0, items.Length())
 	for item := range itemsChan {
 		legendaryItems = append(legendaryItems, item)
 	}
-	close(itemsChan)
+	// Items are parsed concurrently; the page order is restored.
+	sort.SliceStable(legendaryItems, func(i, j int) bool {
+		return legendaryItems[i].Index < legendaryItems[j].Index
+	})
+
+	u := &ServerUpdate{}
 
-	out <- &ServerUpdate{
-		ServerTimestamp: parseTimestamp(s.Find("div.date").Text()),
-		Items:           filterItems(legendaryItems, config),
+	/*
+		Example of an update's date
+
+		<div class="col-xs-5 date"> <i class="fa fa-star"></i> 03/09/2019 - 21:58
+			<br>
+			<small class="text-navy">9 hours 27 min ago.</small>
+		</div>
+	*/
+	rawDate := s.Find("div.date")
+	u.RawRelativeTime = strings.TrimSpace(rawDate.Find("small").Text())
+	u.RawTimestamp = strings.TrimSpace(strings.Replace(rawDate.Text(), u.RawRelativeTime, "", 1))
+
+	t, err := parseTimestamp(u.RawTimestamp, loc)
+	if err != nil {
+		u.Warnings = append(u.Warnings, &ParseWarning{
+			Field:   "server_timestamp",
+			Raw:     u.RawTimestamp,
+			Message: err.Error(),
+		})
+	}
+	u.ServerTimestamp = t
+
+	u.Items = legendaryItems
+
+	age, err := parseRelativeTime(u.RawRelativeTime)
+	if err != nil {
+		u.Warnings = append(u.Warnings, &ParseWarning{
+			Field:   "derived_timestamp",
+			Raw:     u.RawRelativeTime,
+			Message: err.Error(),
+		})
+	} else {
+		u.DerivedTimestamp = date.Add(-age).UTC()
+		if !t.IsZero() {
+			u.UTCOffset = inferUTCOffset(t, u.DerivedTimestamp)
+		}
 	}
+
+	out <- &parsedUpdate{position: position, update: u}
+}
+
+// filterUpdate applies the client-side filtering of the parsing configuration to the items of the
+// server update.
+func filterUpdate(u *ServerUpdate, config *ParserConfig) {
+	u.Items = filterItems(u.Items, config)
+	// The configured predicate may depend on the update's timestamp, hence is applied last.
+	if config.Filter != nil {
+		u.Items = filter(u.Items, func(item *LegendaryItem) bool { return config.Filter(u, item) })
+	}
+}
+
+// assignIDs sets the identifiers of the server updates of a page, newest first, and of their
+// items; the site does not expose any.
+//
+// Items are identified by their timestamp, their properties, and their occurrence among identical
+// items of the same minute, counted from the oldest. Since server-side filtering hides identical
+// items alike, item identifiers do not depend on the parsing configuration.
+//
+// Updates are identified by their timestamp, the properties of their items, and their occurrence
+// among identical updates of the same minute, counted from the oldest. Since server-side filtering
+// changes the items an update lists, update identifiers are only stable across requests made with
+// the same parsing configuration.
+func assignIDs(updates []*ServerUpdate) {
+	updateCount := make(map[string]int)
+	itemCount := make(map[string]int)
+	for i := len(updates) - 1; i >= 0; i-- {
+		u := updates[i]
+		key := u.RawTimestamp
+		for _, item := range u.Items {
+			k := itemKey(item)
+			item.ID = hashID(u.RawTimestamp+k, itemCount[u.RawTimestamp+k])
+			itemCount[u.RawTimestamp+k]++
+			key += k
+		}
+		u.ID = hashID(key, updateCount[key])
+		updateCount[key]++
+	}
+}
+
+// itemKey returns the properties of the item, as displayed by the site.
+func itemKey(item *LegendaryItem) string {
+	return fmt.Sprintf("\x00%s\x00%s\x00%s\x00%s\x00%s\x00%t\x00%s",
+		item.BotName, item.Name, item.Rarity, item.Quality, item.Destination, item.IsIdentified, item.Stats)
+}
+
+// hashID returns a short hash of the key and its occurrence.
+func hashID(key string, occurrence int) string {
+	h := sha1.New()
+	_, _ = fmt.Fprintf(h, "%s\x00%d", key, occurrence)
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// itemWorkers is the number of go routines parsing the items of a single server update.
+const itemWorkers = 4
+
+type itemJob struct {
+	// index is the position of the item within the server update.
+	index int
+	s     *goquery.Selection
 }
 
 func parseLegendaryItemWorker(
 	ctx context.Context,
-	jobs <-chan *goquery.Selection,
+	wg *sync.WaitGroup,
+	jobs <-chan *itemJob,
 	out chan<- *LegendaryItem,
 ) {
+	defer wg.Done()
+	for job := range jobs {
+		if item := parseLegendaryItem(job.index, job.s); item != nil {
+			out <- item
+		}
+	}
+}
+
+// parseLegendaryItem parses the item at the given index of a server update; nil when its quality
+// is below "legendary".
+func parseLegendaryItem(index int, s *goquery.Selection) *LegendaryItem {
 	/*
 		Example of an identified legendary
 
@@ -135,51 +378,133 @@ func parseLegendaryItemWorker(
 			</span>
 		</p>
 	*/
-	for j := range jobs {
-		span := j.Find("span")
-
-		// These attributes are always present; presence feedback is ignored.
-		rawStats, _ := span.Attr("data-content")
-		rawClass, _ := span.Attr("class")
-		rawSpanText := strings.TrimSpace(span.Text())
-
-		q := parseItemQuality(rawClass)
-		// Quality is below "legendary".
-		if q == "" {
-			// Ignore for now.
-			// Lower quality items could be added later on.
-			continue
-		}
-
-		r := parseItemRarity(rawSpanText)
-		n := parseItemName(rawSpanText, r)
+	span := s.Find("span")
+
+	// These attributes are always present; presence feedback is ignored.
+	rawStats, _ := span.Attr("data-content")
+	rawClass, _ := span.Attr("class")
+	rawSpanText := strings.TrimSpace(span.Text())
+
+	q := parseItemQuality(rawClass)
+	// Quality is below "legendary".
+	if q == "" {
+		// Ignore for now.
+		// Lower quality items could be added later on.
+		return nil
+	}
 
-		out <- &LegendaryItem{
-			Name:         n,
-			Rarity:       r,
-			Quality:      q,
-			IsIdentified: n == "unidentified",
-			Destination:  parseDestination(j.Text()),
-			Stats:        parseItemStats(rawStats),
-		}
+	r := parseItemRarity(rawSpanText)
+	n := parseItemName(rawSpanText, r)
+
+	return &LegendaryItem{
+		Index:   index,
+		Name:    n,
+		Rarity:  r,
+		Quality: q,
+		// The site names unidentified items "unidentified".
+		IsIdentified: n != "unidentified",
+		BotName:      parseBotName(s.Text()),
+		Destination:  parseDestination(s.Text()),
+		Stats:        parseItemStats(rawStats),
 	}
 }
 
 var timestampRegex = regexp.MustCompile(`\d{2}/\d{2}/\d{4}\s-\s\d{2}:\d{2}`)
 
-func parseTimestamp(raw string) time.Time {
-	// If an error were to occur, a bad input from the website itself most likely,
-	// the parse function will just return 0001-01-01 00:00:00 +0000 UTC which is acceptable.
+// ErrInvalidTimestamp is returned when a server update timestamp could not be parsed.
+var ErrInvalidTimestamp = errors.New("could not parse server update timestamp")
 
+func parseTimestamp(raw string, loc *time.Location) (time.Time, error) {
+	// Timestamps are rendered in the account's timezone, without any offset information.
 	parsed := timestampRegex.FindString(strings.TrimSpace(raw))
+	if parsed == "" {
+		return time.Time{}, ErrInvalidTimestamp
+	}
 	parsed = strings.ReplaceAll(parsed, " -", "")
 
-	t, _ := time.Parse("02/01/2006 15:04", parsed)
-	return t
+	t, err := time.ParseInLocation("02/01/2006 15:04", parsed, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%v: %v", ErrInvalidTimestamp, err)
+	}
+	return t, nil
 }
 
 var destinationRegex = regexp.MustCompile(`:\s([a-zA-Z]+)`)
 
+var (
+	relativeTimeRegex = regexp.MustCompile(`(\d+)\s*(years?|months?|weeks?|days?|hours?|min(?:ute)?s?|sec(?:ond)?s?)\b`)
+	// relativeTimeFillers are what remains of a relative time once its units are parsed.
+	relativeTimeFillers = regexp.MustCompile(`\b(?:ago|and)\b|[.,]`)
+)
+
+// ErrInvalidRelativeTime is returned when a server update relative time could not be parsed.
+var ErrInvalidRelativeTime = errors.New("could not parse server update relative time")
+
+func parseRelativeTime(raw string) (time.Duration, error) {
+	// The site renders at most two units, i.e. "9 hours 27 min ago." or "10 hours 30 sec ago.".
+	// Months and years are approximated; they are far beyond the activity page's retention anyway.
+	lower := strings.ToLower(raw)
+	matches := relativeTimeRegex.FindAllStringSubmatch(lower, -1)
+	if len(matches) == 0 {
+		return 0, ErrInvalidRelativeTime
+	}
+	// Precaution; an unknown unit would silently shorten the duration.
+	rest := relativeTimeFillers.ReplaceAllString(relativeTimeRegex.ReplaceAllString(lower, ""), "")
+	if rest = strings.Join(strings.Fields(rest), " "); rest != "" {
+		return 0, fmt.Errorf("%w: unparsed %q", ErrInvalidRelativeTime, rest)
+	}
+
+	var d time.Duration
+	for _, m := range matches {
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			return 0, fmt.Errorf("%v: %v", ErrInvalidRelativeTime, err)
+		}
+
+		var unit time.Duration
+		switch strings.TrimSuffix(m[2], "s") {
+		case "year":
+			unit = 365 * 24 * time.Hour
+		case "month":
+			unit = 30 * 24 * time.Hour
+		case "week":
+			unit = 7 * 24 * time.Hour
+		case "day":
+			unit = 24 * time.Hour
+		case "hour":
+			unit = time.Hour
+		case "min", "minute":
+			unit = time.Minute
+		case "sec", "second":
+			unit = time.Second
+		}
+		d += time.Duration(n) * unit
+	}
+	return d, nil
+}
+
+// inferUTCOffset returns the offset of the timezone the site rendered `displayed` in.
+//
+// Both timestamps are truncated by the site (minute resolution), hence the difference is rounded
+// to the nearest quarter of an hour; the granularity of real-world timezone offsets.
+func inferUTCOffset(displayed, derived time.Time) time.Duration {
+	wall := time.Date(
+		displayed.Year(), displayed.Month(), displayed.Day(),
+		displayed.Hour(), displayed.Minute(), 0, 0,
+		time.UTC,
+	)
+	return wall.Sub(derived).Round(15 * time.Minute)
+}
+
+func parseBotName(raw string) string {
+	// The item entry starts with "{bot name}: {destination}".
+	i := strings.Index(raw, ":")
+	if i == -1 {
+		return ""
+	}
+	return strings.TrimSpace(raw[:i])
+}
+
 func parseDestination(raw string) Destination {
 	switch strings.ToLower(destinationRegex.FindStringSubmatch(raw)[1]) {
 	case "salvaged":
@@ -246,7 +571,8 @@ func parseItemRarity(raw string) Rarity {
 }
 
 func parseItemQuality(raw string) Quality {
-	switch strings.ToLower(raw) {
+	// The class attribute is rendered with trailing whitespace, i.e. "text-Legendary ".
+	switch strings.ToLower(strings.TrimSpace(raw)) {
 	case "text-legendary":
 		return QualityNormal
 	case "text-set":
@@ -256,22 +582,29 @@ func parseItemQuality(raw string) Quality {
 	}
 }
 
-func assignSearchParams(config *ParserConfig) string {
+func assignSearchParams(config *ParserConfig) (string, error) {
+	page, err := config.PageIndex()
+	if err != nil {
+		return "", err
+	}
+
 	var (
 		destination,
 		rarity,
 		quality string
 	)
 
+	// Values of the site's 'Item destination' select. Multiple destinations cannot be expressed,
+	// they are filtered client-side.
 	if len(config.Destinations) == 1 {
 		switch config.Destinations[0] {
-		case DestinationStashed:
+		case DestinationSold:
 			destination = "1"
 			break
-		case DestinationSalvaged:
+		case DestinationStashed:
 			destination = "2"
 			break
-		case DestinationSold:
+		case DestinationSalvaged:
 			destination = "4"
 			break
 		}
@@ -279,12 +612,13 @@ func assignSearchParams(config *ParserConfig) string {
 		destination = "All"
 	}
 
+	// The site only distinguishes ancient items (primals included) from the others; `RarityLevel`
+	// being a minimum, non-ancient items must not be excluded server-side.
 	switch config.RarityLevel {
-	case RarityPrimal:
-	case RarityAncient:
+	case RarityPrimal, RarityAncient:
 		rarity = "1"
 	default:
-		rarity = "0"
+		rarity = "All"
 	}
 
 	switch config.Quality {
@@ -301,42 +635,80 @@ func assignSearchParams(config *ParserConfig) string {
 
 	return fmt.Sprintf(
 		"/?item_destination=%s&ancient=%s&item_quality=%s&page=%d",
-		destination, rarity, quality, config.Page,
-	)
+		destination, rarity, quality, page,
+	), nil
 }
 
-// ParserConfig is the parsing configuration
-type ParserConfig struct {
-	Destinations []Destination
-	RarityLevel  Rarity
-	Quality      Quality
-	Page         int8
+// ActivityPage is a single page of the '/bot-activity' page.
+type ActivityPage struct {
+	Updates []*ServerUpdate `json:"updates"`
+	Info    *PageInfo       `json:"page_info"`
 }
 
-// NewParseConfig returns a new instance of `rosbotcollector.ParserConfig` with the default values.
-func NewParseConfig() *ParserConfig {
-	return &ParserConfig{
-		Destinations: []Destination{},
-		RarityLevel:  RarityNonAncient,
-		Quality:      QualityAll,
-		Page:         1,
-	}
+// PageInfo is the pagination metadata of a paged listing, i.e. the '/bot-activity' page.
+// Entries are counted before any of the client-side filtering.
+type PageInfo struct {
+	CurrentPage  PageNumber `json:"current_page"`
+	PageSize     int        `json:"page_size"`
+	FirstEntry   int        `json:"first_entry"`
+	LastEntry    int        `json:"last_entry"`
+	TotalEntries int        `json:"total_entries"`
+	TotalPages   int        `json:"total_pages"`
+	HasNext      bool       `json:"has_next"`
+	HasPrevious  bool       `json:"has_previous"`
+}
+
+// NextPage returns the page following the current one, if any.
+func (i *PageInfo) NextPage() (PageNumber, bool) {
+	return i.CurrentPage + 1, i.HasNext
 }
 
 // ServerUpdate is a Ros-Bot server update.
 type ServerUpdate struct {
-	Items           []*LegendaryItem `json:"legendaries"`
-	ServerTimestamp time.Time        `json:"server_timestamp"`
+	// ID identifies the update across requests made with the same parsing configuration; identical
+	// updates of the same minute are told apart by their order on the page.
+	ID    string           `json:"id"`
+	Items []*LegendaryItem `json:"legendaries"`
+	// ServerTimestamp is the absolute date displayed by the site; minute resolution.
+	ServerTimestamp time.Time `json:"server_timestamp"`
+	// DerivedTimestamp is the response 'Date' header minus the relative time; second resolution
+	// when the site displays seconds.
+	DerivedTimestamp time.Time `json:"derived_timestamp"`
+	// UTCOffset is the offset of the timezone the site rendered `ServerTimestamp` in, as inferred
+	// from `DerivedTimestamp`.
+	UTCOffset       time.Duration   `json:"utc_offset"`
+	RawTimestamp    string          `json:"raw_timestamp"`
+	RawRelativeTime string          `json:"raw_relative_time"`
+	Warnings        []*ParseWarning `json:"warnings,omitempty"`
+}
+
+// ParseWarning is a non-fatal anomaly encountered while parsing a server update.
+// The corresponding field is left to its zero value.
+type ParseWarning struct {
+	Field   string `json:"field"`
+	Raw     string `json:"raw"`
+	Message string `json:"message"`
+}
+
+func (w *ParseWarning) String() string {
+	return fmt.Sprintf("%s: %s (%q)", w.Field, w.Message, w.Raw)
 }
 
 // LegendaryItem is a Diablo III legendary item.
 type LegendaryItem struct {
+	// ID identifies the item across requests, whatever the parsing configuration; identical items
+	// of the same minute are told apart by their order on the page.
+	ID string `json:"id"`
+	// Index is the position of the item within the server update.
+	Index        int         `json:"index"`
 	Name         string      `json:"name"`
 	Quality      Quality     `json:"type"`
 	Rarity       Rarity      `json:"rarity"`
 	Destination  Destination `json:"destination"`
 	IsIdentified bool        `json:"is_identified"`
 	Stats        string      `json:"stats"`
+	// BotName is the name of the hero which collected the item.
+	BotName string `json:"bot_name"`
 }
 
 // Destination is where the bot placed the item upon collection of it.
@@ -1,9 +1,15 @@
 package rosbotcollector
 
 import (
+	"context"
+	"os"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/PuerkitoBio/goquery"
 )
 
 func Test_parseDestination(t *testing.T) {
@@ -61,36 +67,57 @@ func Test_parseDestination(t *testing.T) {
 }
 
 func Test_parseTimestamp(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatalf("could not load location: %v", err)
+	}
 	want, _ := time.Parse("02/01/2006 15:04", "05/10/2001 14:55")
-	wantInvalid, _ := time.Parse("02/01/2006 15:04", "05/10/20 14:55")
+	wantParis, _ := time.ParseInLocation("02/01/2006 15:04", "05/10/2001 14:55", paris)
 
 	type args struct {
 		raw string
+		loc *time.Location
 	}
 	tests := []struct {
-		name string
-		args args
-		want time.Time
+		name    string
+		args    args
+		want    time.Time
+		wantErr bool
 	}{
 		{
 			name: "valid input format",
-			args: args{raw: "05/10/2001 - 14:55"},
+			args: args{raw: "05/10/2001 - 14:55", loc: time.UTC},
 			want: want,
 		},
 		{
-			name: "invalid input format test 1",
-			args: args{raw: "05/10/20 - 14:55"},
-			want: wantInvalid,
+			name: "valid input format with location",
+			args: args{raw: "05/10/2001 - 14:55", loc: paris},
+			want: wantParis,
+		},
+		{
+			name:    "invalid input format test 1",
+			args:    args{raw: "05/10/20 - 14:55", loc: time.UTC},
+			wantErr: true,
+		},
+		{
+			name:    "invalid input format test 2",
+			args:    args{raw: "05/10/20 - 14:55:50", loc: time.UTC},
+			wantErr: true,
 		},
 		{
-			name: "invalid input format test 2",
-			args: args{raw: "05/10/20 - 14:55:50"},
-			want: wantInvalid,
+			name:    "invalid date",
+			args:    args{raw: "35/10/2001 - 14:55", loc: time.UTC},
+			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := parseTimestamp(tt.args.raw); !reflect.DeepEqual(got, tt.want) {
+			got, err := parseTimestamp(tt.args.raw, tt.args.loc)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseTimestamp() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !got.Equal(tt.want) {
 				t.Errorf("parseTimestamp() = %v, want %v", got, tt.want)
 			}
 		})
@@ -136,9 +163,10 @@ func Test_createSearchSegment(t *testing.T) {
 		config ParserConfig
 	}
 	tests := []struct {
-		name string
-		args args
-		want string
+		name    string
+		args    args
+		want    string
+		wantErr bool
 	}{
 		{
 			name: "Multiple destinations",
@@ -153,7 +181,7 @@ func Test_createSearchSegment(t *testing.T) {
 					Page:        1,
 				},
 			},
-			want: "/?item_destination=All&ancient=0&item_quality=3&page=1",
+			want: "/?item_destination=All&ancient=All&item_quality=3&page=0",
 		},
 		{
 			name: "Single destination",
@@ -165,7 +193,7 @@ func Test_createSearchSegment(t *testing.T) {
 					Page:         1,
 				},
 			},
-			want: "/?item_destination=2&ancient=0&item_quality=3&page=1",
+			want: "/?item_destination=4&ancient=All&item_quality=3&page=0",
 		},
 		{
 			name: "Rarity ancient",
@@ -177,7 +205,31 @@ func Test_createSearchSegment(t *testing.T) {
 					Page:         1,
 				},
 			},
-			want: "/?item_destination=2&ancient=1&item_quality=3&page=1",
+			want: "/?item_destination=4&ancient=1&item_quality=3&page=0",
+		},
+		{
+			name: "Rarity primal",
+			args: args{
+				config: ParserConfig{
+					Destinations: []Destination{DestinationStashed},
+					RarityLevel:  RarityPrimal,
+					Quality:      QualityAll,
+					Page:         1,
+				},
+			},
+			want: "/?item_destination=2&ancient=1&item_quality=All&page=0",
+		},
+		{
+			name: "Single destination sold",
+			args: args{
+				config: ParserConfig{
+					Destinations: []Destination{DestinationSold},
+					RarityLevel:  RarityNonAncient,
+					Quality:      QualityNormal,
+					Page:         1,
+				},
+			},
+			want: "/?item_destination=1&ancient=All&item_quality=3&page=0",
 		},
 		{
 			name: "Quality set",
@@ -189,7 +241,7 @@ func Test_createSearchSegment(t *testing.T) {
 					Page:         1,
 				},
 			},
-			want: "/?item_destination=2&ancient=0&item_quality=4&page=1",
+			want: "/?item_destination=4&ancient=All&item_quality=4&page=0",
 		},
 		{
 			name: "All",
@@ -201,12 +253,65 @@ func Test_createSearchSegment(t *testing.T) {
 					Page:         1,
 				},
 			},
-			want: "/?item_destination=All&ancient=0&item_quality=All&page=1",
+			want: "/?item_destination=All&ancient=All&item_quality=All&page=0",
+		},
+		{
+			name: "Page number",
+			args: args{
+				config: ParserConfig{
+					Destinations: []Destination{},
+					RarityLevel:  RarityNonAncient,
+					Quality:      QualityAll,
+					PageNumber:   162,
+				},
+			},
+			want: "/?item_destination=All&ancient=All&item_quality=All&page=161",
+		},
+		{
+			name: "Page number takes precedence over page",
+			args: args{
+				config: ParserConfig{
+					Destinations: []Destination{},
+					RarityLevel:  RarityNonAncient,
+					Quality:      QualityAll,
+					PageNumber:   3,
+					Page:         5,
+				},
+			},
+			want: "/?item_destination=All&ancient=All&item_quality=All&page=2",
+		},
+		{
+			name: "Unset page",
+			args: args{
+				config: ParserConfig{
+					Destinations: []Destination{},
+					RarityLevel:  RarityNonAncient,
+					Quality:      QualityAll,
+				},
+			},
+			want: "/?item_destination=All&ancient=All&item_quality=All&page=0",
+		},
+		{
+			name: "Invalid page",
+			args: args{
+				config: ParserConfig{
+					Destinations: []Destination{},
+					RarityLevel:  RarityNonAncient,
+					Quality:      QualityAll,
+					PageNumber:   -1,
+				},
+			},
+			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := assignSearchParams(&tt.args.config); got != tt.want {
+			got, err := assignSearchParams(&tt.args.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("assignSearchParams() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
 				t.Errorf("assignSearchParams() = %v, want %v", got, tt.want)
 			}
 		})
@@ -288,3 +393,459 @@ func Test_parseItemRarity(t *testing.T) {
 		})
 	}
 }
+
+func Test_parseLegendaryItem(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want *LegendaryItem
+	}{
+		{
+			name: "identified",
+			html: `<p class="m-b-xs">TestDiablo3Name: Stashed <span data-content="Armor" class="text-Legendary ">[Ancient] tyrael's might</span></p>`,
+			want: &LegendaryItem{
+				Index: 2, Name: "tyrael's might", Quality: QualityNormal, Rarity: RarityAncient,
+				Destination: DestinationStashed, IsIdentified: true, Stats: "Armor", BotName: "TestDiablo3Name",
+			},
+		},
+		{
+			name: "identified set",
+			html: `<p class="m-b-xs">TestDiablo3Name: Sold <span data-content="Armor" class="text-Set ">captain crimson's thrust</span></p>`,
+			want: &LegendaryItem{
+				Index: 2, Name: "captain crimson's thrust", Quality: QualitySet, Rarity: RarityNonAncient,
+				Destination: DestinationSold, IsIdentified: true, Stats: "Armor", BotName: "TestDiablo3Name",
+			},
+		},
+		{
+			name: "unidentified",
+			html: `<p class="m-b-xs">TestDiablo3Name: Salvaged <span data-content="Armor" class="text-Legendary "> unidentified</span></p>`,
+			want: &LegendaryItem{
+				Index: 2, Name: "unidentified", Quality: QualityNormal, Rarity: RarityNonAncient,
+				Destination: DestinationSalvaged, IsIdentified: false, Stats: "Armor", BotName: "TestDiablo3Name",
+			},
+		},
+		{
+			name: "below legendary",
+			html: `<p class="m-b-xs">TestDiablo3Name: Salvaged <span data-content="Armor" class="text-Rare ">ring</span></p>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.html))
+			if err != nil {
+				t.Fatalf("could not parse html: %v", err)
+			}
+			if got := parseLegendaryItem(2, doc.Find("p")); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLegendaryItem() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseUpdate(t *testing.T) {
+	file, err := os.Open("./samples/activity.html")
+	if err != nil {
+		t.Errorf("could not open html file")
+	}
+	defer file.Close()
+
+	doc, err := goquery.NewDocumentFromReader(file)
+	if err != nil {
+		t.Fatalf("could not parse html file: %v", err)
+	}
+	valid := doc.Find("div.timeline-item").First()
+
+	invalid, err := goquery.NewDocumentFromReader(strings.NewReader(
+		`<div class="timeline-item"><div class="date">yesterday</div></div>`,
+	))
+	if err != nil {
+		t.Fatalf("could not parse html: %v", err)
+	}
+
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatalf("could not load location: %v", err)
+	}
+	wantTimestamp, _ := time.ParseInLocation("02/01/2006 15:04", "03/09/2019 21:58", paris)
+	// 9 hours 27 min before the response date.
+	date := time.Date(2019, 9, 4, 5, 25, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		s             *goquery.Selection
+		wantTimestamp time.Time
+		wantDerived   time.Time
+		wantOffset    time.Duration
+		wantItems     int
+		wantWarnings  int
+	}{
+		{
+			name:          "valid update",
+			s:             valid,
+			wantTimestamp: wantTimestamp,
+			wantDerived:   time.Date(2019, 9, 3, 19, 58, 0, 0, time.UTC),
+			wantOffset:    2 * time.Hour,
+			wantItems:     11,
+		},
+		{
+			name:         "unparsable timestamp and relative time",
+			s:            invalid.Find("div.timeline-item"),
+			wantWarnings: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := make(chan *parsedUpdate, 1)
+			wg := &sync.WaitGroup{}
+			wg.Add(1)
+			parseUpdate(context.Background(), wg, out, 0, tt.s, paris, date)
+
+			got := (<-out).update
+			if !got.ServerTimestamp.Equal(tt.wantTimestamp) {
+				t.Errorf("parseUpdate() timestamp = %v, want %v", got.ServerTimestamp, tt.wantTimestamp)
+			}
+			if !got.DerivedTimestamp.Equal(tt.wantDerived) {
+				t.Errorf("parseUpdate() derived = %v, want %v", got.DerivedTimestamp, tt.wantDerived)
+			}
+			if got.UTCOffset != tt.wantOffset {
+				t.Errorf("parseUpdate() offset = %v, want %v", got.UTCOffset, tt.wantOffset)
+			}
+			if len(got.Items) != tt.wantItems {
+				t.Errorf("parseUpdate() items = %v, want %v", len(got.Items), tt.wantItems)
+			}
+			if len(got.Warnings) != tt.wantWarnings {
+				t.Errorf("parseUpdate() warnings = %v, want %v", got.Warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func Test_sortOldestFirst(t *testing.T) {
+	ts := time.Date(2019, 9, 3, 21, 58, 0, 0, time.UTC)
+	// Newest first, as listed by the page; a, b and c are of the same minute.
+	updates := []*ServerUpdate{
+		{ID: "d", ServerTimestamp: ts.Add(time.Minute)},
+		{ID: "c", ServerTimestamp: ts},
+		{ID: "b", ServerTimestamp: ts},
+		{ID: "a", ServerTimestamp: ts},
+		{ID: "e", ServerTimestamp: ts.Add(-time.Minute)},
+	}
+	sortOldestFirst(updates)
+
+	var got []string
+	for _, u := range updates {
+		got = append(got, u.ID)
+	}
+	if want := []string{"e", "a", "b", "c", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sortOldestFirst() = %v, want %v", got, want)
+	}
+}
+
+func Test_assignIDs(t *testing.T) {
+	newUpdate := func(raw string, names ...string) *ServerUpdate {
+		u := &ServerUpdate{RawTimestamp: raw}
+		for i, name := range names {
+			u.Items = append(u.Items, &LegendaryItem{Index: i, Name: name, Rarity: RarityAncient, BotName: "Bot"})
+		}
+		return u
+	}
+	ids := func(updates []*ServerUpdate) (updateIDs, itemIDs []string) {
+		for _, u := range updates {
+			updateIDs = append(updateIDs, u.ID)
+			for _, item := range u.Items {
+				itemIDs = append(itemIDs, item.ID)
+			}
+		}
+		return updateIDs, itemIDs
+	}
+
+	// Newest first; the two oldest updates are identical drops of the same minute.
+	page := []*ServerUpdate{
+		newUpdate("03/09/2019 - 21:59", "Ring of Royal Grandeur"),
+		newUpdate("03/09/2019 - 21:58", "Convention of Elements", "Ring of Royal Grandeur"),
+		newUpdate("03/09/2019 - 21:58", "Convention of Elements", "Ring of Royal Grandeur"),
+	}
+	assignIDs(page)
+	updateIDs, itemIDs := ids(page)
+	for _, list := range [][]string{updateIDs, itemIDs} {
+		seen := make(map[string]bool)
+		for _, id := range list {
+			if seen[id] {
+				t.Fatalf("assignIDs() duplicate ID %q among %v", id, list)
+			}
+			seen[id] = true
+		}
+	}
+
+	// A newer update does not change the identifiers of the older ones.
+	next := append([]*ServerUpdate{newUpdate("03/09/2019 - 22:00", "Convention of Elements")},
+		newUpdate("03/09/2019 - 21:59", "Ring of Royal Grandeur"),
+		newUpdate("03/09/2019 - 21:58", "Convention of Elements", "Ring of Royal Grandeur"),
+		newUpdate("03/09/2019 - 21:58", "Convention of Elements", "Ring of Royal Grandeur"),
+	)
+	assignIDs(next)
+	nextUpdateIDs, nextItemIDs := ids(next[1:])
+	if !reflect.DeepEqual(nextUpdateIDs, updateIDs) || !reflect.DeepEqual(nextItemIDs, itemIDs) {
+		t.Errorf("assignIDs() = %v, %v, want %v, %v", nextUpdateIDs, nextItemIDs, updateIDs, itemIDs)
+	}
+
+	// Item identifiers survive server-side filtering, which hides sibling items.
+	filtered := []*ServerUpdate{
+		newUpdate("03/09/2019 - 21:58", "Ring of Royal Grandeur"),
+		newUpdate("03/09/2019 - 21:58", "Ring of Royal Grandeur"),
+	}
+	filtered[0].Items[0].Index, filtered[1].Items[0].Index = 1, 1
+	assignIDs(filtered)
+	_, filteredItemIDs := ids(filtered)
+	if want := []string{itemIDs[2], itemIDs[4]}; !reflect.DeepEqual(filteredItemIDs, want) {
+		t.Errorf("assignIDs() filtered items = %v, want %v", filteredItemIDs, want)
+	}
+}
+
+func Test_parseRelativeTime(t *testing.T) {
+	type args struct {
+		raw string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    time.Duration
+		wantErr bool
+	}{
+		{
+			name: "hours and minutes",
+			args: args{raw: "9 hours 27 min ago."},
+			want: 9*time.Hour + 27*time.Minute,
+		},
+		{
+			name: "hours and seconds",
+			args: args{raw: "10 hours 30 sec ago."},
+			want: 10*time.Hour + 30*time.Second,
+		},
+		{
+			name: "singular units",
+			args: args{raw: "1 day 1 hour ago."},
+			want: 25 * time.Hour,
+		},
+		{
+			name: "weeks",
+			args: args{raw: "2 weeks 3 days ago."},
+			want: 17 * 24 * time.Hour,
+		},
+		{
+			name: "long units",
+			args: args{raw: "1 minute 45 seconds ago."},
+			want: time.Minute + 45*time.Second,
+		},
+		{
+			name: "plural short units",
+			args: args{raw: "2 mins 1 secs ago."},
+			want: 2*time.Minute + time.Second,
+		},
+		{
+			name: "conjunction",
+			args: args{raw: "3 hours, and 2 minutes ago."},
+			want: 3*time.Hour + 2*time.Minute,
+		},
+		{
+			name:    "invalid",
+			args:    args{raw: "yesterday"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown unit",
+			args:    args{raw: "9 hours 27 fortnights ago."},
+			wantErr: true,
+		},
+		{
+			name:    "not fully consumed",
+			args:    args{raw: "about 9 hours ago."},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRelativeTime(tt.args.raw)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseRelativeTime() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("parseRelativeTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_inferUTCOffset(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("could not load location: %v", err)
+	}
+
+	type args struct {
+		displayed time.Time
+		derived   time.Time
+	}
+	tests := []struct {
+		name string
+		args args
+		want time.Duration
+	}{
+		{
+			name: "UTC",
+			args: args{
+				displayed: time.Date(2019, 9, 3, 21, 58, 0, 0, time.UTC),
+				derived:   time.Date(2019, 9, 3, 21, 58, 41, 0, time.UTC),
+			},
+			want: 0,
+		},
+		{
+			name: "negative offset parsed as UTC",
+			args: args{
+				displayed: time.Date(2019, 9, 3, 17, 58, 0, 0, time.UTC),
+				derived:   time.Date(2019, 9, 3, 21, 57, 12, 0, time.UTC),
+			},
+			want: -4 * time.Hour,
+		},
+		{
+			name: "wall clock is used regardless of location",
+			args: args{
+				displayed: time.Date(2019, 9, 3, 17, 58, 0, 0, newYork),
+				derived:   time.Date(2019, 9, 3, 21, 58, 0, 0, time.UTC),
+			},
+			want: -4 * time.Hour,
+		},
+		{
+			name: "half hour offset",
+			args: args{
+				displayed: time.Date(2019, 9, 4, 3, 29, 0, 0, time.UTC),
+				derived:   time.Date(2019, 9, 3, 21, 58, 0, 0, time.UTC),
+			},
+			want: 5*time.Hour + 30*time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inferUTCOffset(tt.args.displayed, tt.args.derived); got != tt.want {
+				t.Errorf("inferUTCOffset() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parsePageInfo(t *testing.T) {
+	file, err := os.Open("./samples/activity.html")
+	if err != nil {
+		t.Errorf("could not open html file")
+	}
+	defer file.Close()
+
+	doc, err := goquery.NewDocumentFromReader(file)
+	if err != nil {
+		t.Fatalf("could not parse html file: %v", err)
+	}
+
+	newDoc := func(html string) *goquery.Selection {
+		d, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+		if err != nil {
+			t.Fatalf("could not parse html: %v", err)
+		}
+		return d.Selection
+	}
+
+	tests := []struct {
+		name string
+		s    *goquery.Selection
+		want PageInfo
+	}{
+		{
+			name: "first page",
+			s:    doc.Selection,
+			want: PageInfo{
+				CurrentPage:  1,
+				PageSize:     50,
+				FirstEntry:   1,
+				LastEntry:    50,
+				TotalEntries: 8072,
+				TotalPages:   162,
+				HasNext:      true,
+				HasPrevious:  false,
+			},
+		},
+		{
+			name: "last page",
+			s: newDoc(`
+				<div class="view-header">Displaying 8051 - 8072 of 8072</div>
+				<ul class="pagination">
+					<li class="pager-first"><a href="/user/1/bot-activity">« first</a></li>
+					<li class="prev"><a href="/user/1/bot-activity?page=160">‹ previous</a></li>
+					<li class="active"><span>162</span></li>
+				</ul>`,
+			),
+			want: PageInfo{
+				CurrentPage:  162,
+				PageSize:     50,
+				FirstEntry:   8051,
+				LastEntry:    8072,
+				TotalEntries: 8072,
+				TotalPages:   162,
+				HasNext:      false,
+				HasPrevious:  true,
+			},
+		},
+		{
+			name: "single page",
+			s:    newDoc(`<div class="view-header">Displaying 1 - 3 of 3</div>`),
+			want: PageInfo{
+				CurrentPage:  1,
+				PageSize:     3,
+				FirstEntry:   1,
+				LastEntry:    3,
+				TotalEntries: 3,
+				TotalPages:   1,
+			},
+		},
+		{
+			name: "no entries",
+			s:    newDoc(`<div class="view-empty">No activity.</div>`),
+			want: PageInfo{CurrentPage: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parsePageInfo(tt.s); *got != tt.want {
+				t.Errorf("parsePageInfo() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseBotName(t *testing.T) {
+	type args struct {
+		raw string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "Bot name",
+			args: args{raw: "TestDiablo3Name: Salvaged unidentified"},
+			want: "TestDiablo3Name",
+		},
+		{
+			name: "No bot name",
+			args: args{raw: "unidentified"},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseBotName(tt.args.raw); got != tt.want {
+				t.Errorf("parseBotName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,512 @@
+package rosbotcollector
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+/*
+	Query language
+
+	expr       = term { "or" term }
+	term       = factor { "and" factor }
+	factor     = "not" factor | "(" expr ")" | comparison
+	comparison = field operator value | field "in" "(" value { "," value } ")"
+	operator   = "=" | "!=" | "~" | "!~" | ">" | ">=" | "<" | "<="
+
+	Fields and their operators:
+
+	name, bot              = != ~ !~ in     ("~" is a case-insensitive substring match)
+	rarity                 = != > >= < <= in (non-ancient < ancient < primal)
+	quality                = != in          (normal|legendary, set)
+	dest, destination      = != in          (stashed, salvaged, sold)
+	identified             = !=             (true, false)
+	time, timestamp        = > >= < <=      (RFC 3339, "2006-01-02 15:04" or "2006-01-02")
+	stats                  ~ !~
+
+	Example: rarity>=ancient and dest in (stashed,sold) and name~"tyrael"
+*/
+
+// ErrInvalidQuery is returned when a query could not be parsed.
+var ErrInvalidQuery = errors.New("invalid query")
+
+// Query is a textual filter expression, parsed by `ParseQuery`.
+//
+// It implements `flag.Value` and `encoding.TextUnmarshaler` so that it can be read from CLI flags
+// and configuration files.
+type Query struct {
+	src       string
+	predicate Predicate
+}
+
+// ParseQuery parses a textual filter expression into a predicate.
+// i.e. `rarity>=ancient and dest in (stashed,sold) and name~"tyrael"`.
+func ParseQuery(s string) (Predicate, error) {
+	tokens, err := lexQuery(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{tokens: tokens}
+
+	predicate, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.errorf(t, "unexpected %q", t.text)
+	}
+	return predicate, nil
+}
+
+// Predicate returns the parsed predicate; nil when the query is empty.
+func (q *Query) Predicate() Predicate {
+	return q.predicate
+}
+
+// String returns the query as it was parsed.
+func (q *Query) String() string {
+	return q.src
+}
+
+// Set parses `s`; it implements `flag.Value`.
+func (q *Query) Set(s string) error {
+	if strings.TrimSpace(s) == "" {
+		q.src, q.predicate = "", nil
+		return nil
+	}
+	p, err := ParseQuery(s)
+	if err != nil {
+		return err
+	}
+	q.src, q.predicate = s, p
+	return nil
+}
+
+// UnmarshalText implements `encoding.TextUnmarshaler`.
+func (q *Query) UnmarshalText(text []byte) error {
+	return q.Set(string(text))
+}
+
+// MarshalText implements `encoding.TextMarshaler`.
+func (q Query) MarshalText() ([]byte, error) {
+	return []byte(q.src), nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func lexQuery(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		c := s[i]
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: i})
+			i++
+		case c == '"':
+			// Find the closing quote, skipping escaped ones.
+			j := i + 1
+			for ; j < len(s) && s[j] != '"'; j++ {
+				if s[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(s) {
+				return nil, fmt.Errorf("%w: unterminated string at position %d", ErrInvalidQuery, i)
+			}
+			text, err := strconv.Unquote(s[i : j+1])
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid string at position %d", ErrInvalidQuery, i)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, pos: i})
+			i = j + 1
+		case strings.ContainsRune("=!~<>", rune(c)):
+			j := i + 1
+			if j < len(s) && (s[j] == '=' || (c == '!' && s[j] == '~')) {
+				j++
+			}
+			op := s[i:j]
+			if op == "!" {
+				return nil, fmt.Errorf("%w: unexpected %q at position %d", ErrInvalidQuery, op, i)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: op, pos: i})
+			i = j
+		case isIdentRune(r):
+			j := i + size
+			for j < len(s) {
+				r, size := utf8.DecodeRuneInString(s[j:])
+				if !isIdentRune(r) {
+					break
+				}
+				j += size
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: s[i:j], pos: i})
+			i = j
+		default:
+			return nil, fmt.Errorf("%w: unexpected %q at position %d", ErrInvalidQuery, r, i)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(s)}), nil
+}
+
+func isIdentRune(r rune) bool {
+	// Dashes, colons and dots allow for unquoted timestamps and rarities, i.e. "non-ancient".
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("-_:.'", r)
+}
+
+type queryParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *queryParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *queryParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *queryParser) isKeyword(t token, keyword string) bool {
+	return t.kind == tokenIdent && strings.EqualFold(t.text, keyword)
+}
+
+func (p *queryParser) errorf(t token, format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s at position %d", ErrInvalidQuery, fmt.Sprintf(format, args...), t.pos)
+}
+
+func (p *queryParser) parseExpr() (Predicate, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	predicates := []Predicate{left}
+	for p.isKeyword(p.peek(), "or") {
+		p.next()
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, right)
+	}
+	if len(predicates) == 1 {
+		return left, nil
+	}
+	return Or(predicates...), nil
+}
+
+func (p *queryParser) parseTerm() (Predicate, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	predicates := []Predicate{left}
+	for p.isKeyword(p.peek(), "and") {
+		p.next()
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, right)
+	}
+	if len(predicates) == 1 {
+		return left, nil
+	}
+	return And(predicates...), nil
+}
+
+func (p *queryParser) parseFactor() (Predicate, error) {
+	t := p.peek()
+	switch {
+	case p.isKeyword(t, "not"):
+		p.next()
+		f, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return Not(f), nil
+	case t.kind == tokenLParen:
+		p.next()
+		e, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokenRParen {
+			return nil, p.errorf(t, "expected \")\"")
+		}
+		return e, nil
+	default:
+		return p.parseComparison()
+	}
+}
+
+func (p *queryParser) parseComparison() (Predicate, error) {
+	field := p.next()
+	if field.kind != tokenIdent {
+		return nil, p.errorf(field, "expected a field")
+	}
+
+	op := p.next()
+	var values []token
+	switch {
+	case op.kind == tokenOperator:
+		v := p.next()
+		if v.kind != tokenIdent && v.kind != tokenString {
+			return nil, p.errorf(v, "expected a value")
+		}
+		values = append(values, v)
+	case p.isKeyword(op, "in"):
+		op.text = "in"
+		if t := p.next(); t.kind != tokenLParen {
+			return nil, p.errorf(t, "expected \"(\"")
+		}
+		for {
+			v := p.next()
+			if v.kind != tokenIdent && v.kind != tokenString {
+				return nil, p.errorf(v, "expected a value")
+			}
+			values = append(values, v)
+
+			t := p.next()
+			if t.kind == tokenRParen {
+				break
+			}
+			if t.kind != tokenComma {
+				return nil, p.errorf(t, "expected \",\" or \")\"")
+			}
+		}
+	default:
+		return nil, p.errorf(op, "expected an operator")
+	}
+
+	switch strings.ToLower(field.text) {
+	case "name":
+		return p.stringComparison(op, values, NameIs, NameContains)
+	case "bot":
+		return p.stringComparison(op, values, BotIs, BotContains)
+	case "stats":
+		if op.text != "~" && op.text != "!~" {
+			return nil, p.errorf(op, "operator %q is not supported by %q", op.text, field.text)
+		}
+		return p.stringComparison(op, values, nil, StatsContain)
+	case "rarity":
+		return p.rarityComparison(op, values)
+	case "quality":
+		return p.qualityComparison(op, values)
+	case "dest", "destination":
+		return p.destinationComparison(op, values)
+	case "identified":
+		return p.identifiedComparison(op, values)
+	case "time", "timestamp":
+		return p.timeComparison(op, values)
+	default:
+		return nil, p.errorf(field, "unknown field %q", field.text)
+	}
+}
+
+func (p *queryParser) stringComparison(
+	op token,
+	values []token,
+	equal func(string) Predicate,
+	contains func(string) Predicate,
+) (Predicate, error) {
+	switch op.text {
+	case "~", "!~":
+		if op.text == "!~" {
+			return Not(contains(values[0].text)), nil
+		}
+		return contains(values[0].text), nil
+	case "=", "!=", "in":
+		if equal == nil {
+			break
+		}
+		predicates := make([]Predicate, 0, len(values))
+		for _, v := range values {
+			predicates = append(predicates, equal(v.text))
+		}
+		if op.text == "!=" {
+			return Not(predicates[0]), nil
+		}
+		return Or(predicates...), nil
+	}
+	return nil, p.errorf(op, "operator %q is not supported", op.text)
+}
+
+func (p *queryParser) rarityComparison(op token, values []token) (Predicate, error) {
+	rarities := make([]Rarity, 0, len(values))
+	for _, v := range values {
+		r, ok := parseQueryRarity(v.text)
+		if !ok {
+			return nil, p.errorf(v, "unknown rarity %q", v.text)
+		}
+		rarities = append(rarities, r)
+	}
+
+	r := rarities[0]
+	switch op.text {
+	case "=", "in":
+		return RarityIs(rarities...), nil
+	case "!=":
+		return Not(RarityIs(r)), nil
+	case ">=":
+		return RarityAtLeast(r), nil
+	case ">":
+		return Not(RarityAtMost(r)), nil
+	case "<=":
+		return RarityAtMost(r), nil
+	case "<":
+		return Not(RarityAtLeast(r)), nil
+	}
+	return nil, p.errorf(op, "operator %q is not supported by \"rarity\"", op.text)
+}
+
+func parseQueryRarity(raw string) (Rarity, bool) {
+	switch strings.ToLower(raw) {
+	case "primal":
+		return RarityPrimal, true
+	case "ancient":
+		return RarityAncient, true
+	case "non-ancient", "nonancient", "normal":
+		return RarityNonAncient, true
+	}
+	return "", false
+}
+
+func (p *queryParser) qualityComparison(op token, values []token) (Predicate, error) {
+	qualities := make([]Quality, 0, len(values))
+	for _, v := range values {
+		var q Quality
+		switch strings.ToLower(v.text) {
+		case "normal", "legendary":
+			q = QualityNormal
+		case "set":
+			q = QualitySet
+		default:
+			return nil, p.errorf(v, "unknown quality %q", v.text)
+		}
+		qualities = append(qualities, q)
+	}
+
+	switch op.text {
+	case "=", "in":
+		return QualityIs(qualities...), nil
+	case "!=":
+		return Not(QualityIs(qualities[0])), nil
+	}
+	return nil, p.errorf(op, "operator %q is not supported by \"quality\"", op.text)
+}
+
+func (p *queryParser) destinationComparison(op token, values []token) (Predicate, error) {
+	destinations := make([]Destination, 0, len(values))
+	for _, v := range values {
+		var d Destination
+		switch strings.ToLower(v.text) {
+		case "stashed":
+			d = DestinationStashed
+		case "salvaged":
+			d = DestinationSalvaged
+		case "sold":
+			d = DestinationSold
+		default:
+			return nil, p.errorf(v, "unknown destination %q", v.text)
+		}
+		destinations = append(destinations, d)
+	}
+
+	switch op.text {
+	case "=", "in":
+		return DestinationIn(destinations...), nil
+	case "!=":
+		return Not(DestinationIn(destinations[0])), nil
+	}
+	return nil, p.errorf(op, "operator %q is not supported by \"destination\"", op.text)
+}
+
+func (p *queryParser) identifiedComparison(op token, values []token) (Predicate, error) {
+	b, err := strconv.ParseBool(values[0].text)
+	if err != nil {
+		return nil, p.errorf(values[0], "expected true or false")
+	}
+
+	switch op.text {
+	case "=":
+		return Identified(b), nil
+	case "!=":
+		return Identified(!b), nil
+	}
+	return nil, p.errorf(op, "operator %q is not supported by \"identified\"", op.text)
+}
+
+func (p *queryParser) timeComparison(op token, values []token) (Predicate, error) {
+	var (
+		t      time.Time
+		layout string
+		err    error
+	)
+	for _, layout = range []string{time.RFC3339, "2006-01-02 15:04", "2006-01-02"} {
+		if t, err = time.Parse(layout, values[0].text); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, p.errorf(values[0], "invalid time %q", values[0].text)
+	}
+
+	// `CollectedBetween` is [from, to); the timestamps have minute resolution.
+	var between func(t time.Time) Predicate
+	switch op.text {
+	case ">=":
+		between = func(t time.Time) Predicate { return CollectedBetween(t, time.Time{}) }
+	case ">":
+		between = func(t time.Time) Predicate { return CollectedBetween(t.Add(time.Nanosecond), time.Time{}) }
+	case "<":
+		between = func(t time.Time) Predicate { return CollectedBetween(time.Time{}, t) }
+	case "<=":
+		between = func(t time.Time) Predicate { return CollectedBetween(time.Time{}, t.Add(time.Nanosecond)) }
+	default:
+		return nil, p.errorf(op, "operator %q is not supported by \"time\"", op.text)
+	}
+	if layout == time.RFC3339 {
+		return between(t), nil
+	}
+
+	// Without an offset the literal is a wall-clock time in the timezone
+	// the update was rendered in, like the timestamps on the Ros-Bot pages.
+	return func(u *ServerUpdate, item *LegendaryItem) bool {
+		local := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, u.ServerTimestamp.Location())
+		return between(local)(u, item)
+	}, nil
+}
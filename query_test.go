@@ -0,0 +1,184 @@
+package rosbotcollector
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "Example",
+			query: `rarity>=ancient and dest in (stashed,sold) and name~"tyrael"`,
+			want:  []string{"tyrael's might"},
+		},
+		{
+			name:  "Precedence",
+			query: `bot=crusader or rarity=ancient and dest=stashed`,
+			want:  []string{"tyrael's might", "captain crimson's trimmings"},
+		},
+		{
+			name:  "Parentheses and not",
+			query: `not (rarity > ancient or identified = false)`,
+			want:  []string{"tyrael's might"},
+		},
+		{
+			name:  "Rarity below",
+			query: `rarity<ancient`,
+			want:  []string{"unidentified"},
+		},
+		{
+			name:  "Quality",
+			query: `quality = set`,
+			want:  []string{"captain crimson's trimmings"},
+		},
+		{
+			name:  "Time",
+			query: `time >= "2019-09-03 21:00" and time < 2019-09-04`,
+			want:  []string{"captain crimson's trimmings"},
+		},
+		{
+			name:  "Stats",
+			query: `stats ~ "+650 strength"`,
+			want:  []string{"captain crimson's trimmings"},
+		},
+		{
+			name:  "Name not contains",
+			query: `NAME !~ unidentified AND bot != crusader`,
+			want:  []string{"tyrael's might"},
+		},
+		{
+			name:    "Unknown field",
+			query:   `colour = red`,
+			wantErr: true,
+		},
+		{
+			name:    "Unknown rarity",
+			query:   `rarity >= legendary`,
+			wantErr: true,
+		},
+		{
+			name:    "Unsupported operator",
+			query:   `dest >= stashed`,
+			wantErr: true,
+		},
+		{
+			name:    "Unterminated string",
+			query:   `name ~ "tyrael`,
+			wantErr: true,
+		},
+		{
+			name:    "Unbalanced parentheses",
+			query:   `(rarity = primal`,
+			wantErr: true,
+		},
+		{
+			name:    "Trailing tokens",
+			query:   `rarity = primal primal`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ParseQuery(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseQuery() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				if !errors.Is(err, ErrInvalidQuery) {
+					t.Errorf("ParseQuery() error = %v, want %v", err, ErrInvalidQuery)
+				}
+				return
+			}
+			if got := itemNames(FilterUpdates(testUpdates(), p)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseQuery() matched %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseQuery_localTimeAndNames(t *testing.T) {
+	paris := time.FixedZone("CEST", 2*60*60)
+	updates := []*ServerUpdate{
+		{
+			// 20:30 UTC.
+			ServerTimestamp: time.Date(2019, 9, 3, 22, 30, 0, 0, paris),
+			Items:           []*LegendaryItem{{Name: "überhammer"}},
+		},
+	}
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			name:  "Wall-clock time in the update's timezone",
+			query: `time >= "2019-09-03 22:00" and time < 2019-09-04`,
+			want:  []string{"überhammer"},
+		},
+		{
+			name:  "Wall-clock time is not UTC",
+			query: `time < "2019-09-03 21:00"`,
+		},
+		{
+			name:  "RFC 3339 keeps its offset",
+			query: `time < "2019-09-03T21:00:00Z"`,
+			want:  []string{"überhammer"},
+		},
+		{
+			name:  "Unquoted non-ASCII name",
+			query: `name = überhammer`,
+			want:  []string{"überhammer"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ParseQuery() error = %v", err)
+			}
+			if got := itemNames(FilterUpdates(updates, p)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseQuery() matched %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuery_flagAndText(t *testing.T) {
+	var q Query
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&q, "filter", "")
+	if err := fs.Parse([]string{"-filter", "rarity=primal"}); err != nil {
+		t.Fatalf("flag.Parse() error = %v", err)
+	}
+	if got := itemNames(FilterUpdates(testUpdates(), q.Predicate())); !reflect.DeepEqual(got, []string{"captain crimson's trimmings"}) {
+		t.Errorf("Query.Set() matched %v", got)
+	}
+
+	var config struct {
+		Filter Query `json:"filter"`
+	}
+	if err := json.Unmarshal([]byte(`{"filter": "dest = stashed"}`), &config); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if got := config.Filter.String(); got != "dest = stashed" {
+		t.Errorf("Query.String() = %v", got)
+	}
+	if got := itemNames(FilterUpdates(testUpdates(), config.Filter.Predicate())); !reflect.DeepEqual(got, []string{"tyrael's might"}) {
+		t.Errorf("Query.UnmarshalText() matched %v", got)
+	}
+
+	if err := json.Unmarshal([]byte(`{"filter": "dest = ~"}`), &config); err == nil {
+		t.Errorf("Query.UnmarshalText() expected an error")
+	}
+}
@@ -0,0 +1,181 @@
+package rpc
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	rbc "github.com/maxzaleski/go-rosbot-collector"
+	"github.com/maxzaleski/go-rosbot-collector/rpc/rosbotpb"
+)
+
+var (
+	rarities = map[rbc.Rarity]rosbotpb.Rarity{
+		rbc.RarityNonAncient: rosbotpb.Rarity_RARITY_NON_ANCIENT,
+		rbc.RarityAncient:    rosbotpb.Rarity_RARITY_ANCIENT,
+		rbc.RarityPrimal:     rosbotpb.Rarity_RARITY_PRIMAL,
+	}
+	qualities = map[rbc.Quality]rosbotpb.Quality{
+		rbc.QualityAll:    rosbotpb.Quality_QUALITY_ALL,
+		rbc.QualityNormal: rosbotpb.Quality_QUALITY_NORMAL,
+		rbc.QualitySet:    rosbotpb.Quality_QUALITY_SET,
+	}
+	destinations = map[rbc.Destination]rosbotpb.Destination{
+		rbc.DestinationStashed:  rosbotpb.Destination_DESTINATION_STASHED,
+		rbc.DestinationSalvaged: rosbotpb.Destination_DESTINATION_SALVAGED,
+		rbc.DestinationSold:     rosbotpb.Destination_DESTINATION_SOLD,
+		rbc.DestinationUnknown:  rosbotpb.Destination_DESTINATION_UNKNOWN,
+	}
+)
+
+// The inverse mappings, from the protocol buffer enums.
+var (
+	rarityValues      = make(map[rosbotpb.Rarity]rbc.Rarity, len(rarities))
+	qualityValues     = make(map[rosbotpb.Quality]rbc.Quality, len(qualities))
+	destinationValues = make(map[rosbotpb.Destination]rbc.Destination, len(destinations))
+)
+
+func init() {
+	for k, v := range rarities {
+		rarityValues[v] = k
+	}
+	for k, v := range qualities {
+		qualityValues[v] = k
+	}
+	for k, v := range destinations {
+		destinationValues[v] = k
+	}
+}
+
+// UpdateToProto converts a server update into its protocol buffer message.
+func UpdateToProto(u *rbc.ServerUpdate) *rosbotpb.ServerUpdate {
+	p := &rosbotpb.ServerUpdate{
+		Id:              u.ID,
+		Items:           make([]*rosbotpb.LegendaryItem, 0, len(u.Items)),
+		UtcOffset:       durationpb.New(u.UTCOffset),
+		RawTimestamp:    u.RawTimestamp,
+		RawRelativeTime: u.RawRelativeTime,
+	}
+	// Zero times are left unset, rather than encoded as year 1.
+	if !u.ServerTimestamp.IsZero() {
+		p.ServerTimestamp = timestamppb.New(u.ServerTimestamp)
+	}
+	if !u.DerivedTimestamp.IsZero() {
+		p.DerivedTimestamp = timestamppb.New(u.DerivedTimestamp)
+	}
+	for _, item := range u.Items {
+		p.Items = append(p.Items, &rosbotpb.LegendaryItem{
+			Id:           item.ID,
+			Index:        int32(item.Index),
+			Name:         item.Name,
+			Quality:      qualities[item.Quality],
+			Rarity:       rarities[item.Rarity],
+			Destination:  destinations[item.Destination],
+			IsIdentified: item.IsIdentified,
+			Stats:        item.Stats,
+			BotName:      item.BotName,
+		})
+	}
+	for _, w := range u.Warnings {
+		p.Warnings = append(p.Warnings, &rosbotpb.ParseWarning{Field: w.Field, Raw: w.Raw, Message: w.Message})
+	}
+	return p
+}
+
+// UpdateFromProto converts a protocol buffer message into a server update. Timestamps are in UTC.
+func UpdateFromProto(p *rosbotpb.ServerUpdate) *rbc.ServerUpdate {
+	u := &rbc.ServerUpdate{
+		ID:              p.GetId(),
+		Items:           make([]*rbc.LegendaryItem, 0, len(p.GetItems())),
+		UTCOffset:       p.GetUtcOffset().AsDuration(),
+		RawTimestamp:    p.GetRawTimestamp(),
+		RawRelativeTime: p.GetRawRelativeTime(),
+	}
+	if p.GetServerTimestamp() != nil {
+		u.ServerTimestamp = p.GetServerTimestamp().AsTime()
+	}
+	if p.GetDerivedTimestamp() != nil {
+		u.DerivedTimestamp = p.GetDerivedTimestamp().AsTime()
+	}
+	for _, item := range p.GetItems() {
+		u.Items = append(u.Items, &rbc.LegendaryItem{
+			ID:           item.GetId(),
+			Index:        int(item.GetIndex()),
+			Name:         item.GetName(),
+			Quality:      qualityValues[item.GetQuality()],
+			Rarity:       rarityValues[item.GetRarity()],
+			Destination:  destinationValues[item.GetDestination()],
+			IsIdentified: item.GetIsIdentified(),
+			Stats:        item.GetStats(),
+			BotName:      item.GetBotName(),
+		})
+	}
+	for _, w := range p.GetWarnings() {
+		u.Warnings = append(u.Warnings, &rbc.ParseWarning{Field: w.GetField(), Raw: w.GetRaw(), Message: w.GetMessage()})
+	}
+	return u
+}
+
+// PageInfoToProto converts pagination metadata into its protocol buffer message.
+func PageInfoToProto(i *rbc.PageInfo) *rosbotpb.PageInfo {
+	if i == nil {
+		return nil
+	}
+	return &rosbotpb.PageInfo{
+		CurrentPage:  int32(i.CurrentPage),
+		PageSize:     int32(i.PageSize),
+		FirstEntry:   int32(i.FirstEntry),
+		LastEntry:    int32(i.LastEntry),
+		TotalEntries: int32(i.TotalEntries),
+		TotalPages:   int32(i.TotalPages),
+		HasNext:      i.HasNext,
+		HasPrevious:  i.HasPrevious,
+	}
+}
+
+// ConfigFromProto converts a protocol buffer message into a validated parsing configuration.
+// Unspecified fields keep the values of `rosbotcollector.NewParseConfig`.
+func ConfigFromProto(p *rosbotpb.ParserConfig) (*rbc.ParserConfig, error) {
+	c := rbc.NewParseConfig()
+	for _, d := range p.GetDestinations() {
+		v, ok := destinationValues[d]
+		if !ok || v == rbc.DestinationUnknown {
+			return nil, fmt.Errorf("%w %s", rbc.ErrInvalidDestination, d)
+		}
+		c.Destinations = append(c.Destinations, v)
+	}
+	if r := p.GetRarityLevel(); r != rosbotpb.Rarity_RARITY_UNSPECIFIED {
+		v, ok := rarityValues[r]
+		if !ok {
+			return nil, fmt.Errorf("%w %s", rbc.ErrInvalidRarity, r)
+		}
+		c.MinRarity(v)
+	}
+	if q := p.GetQuality(); q != rosbotpb.Quality_QUALITY_UNSPECIFIED {
+		v, ok := qualityValues[q]
+		if !ok {
+			return nil, fmt.Errorf("%w %s", rbc.ErrInvalidQuality, q)
+		}
+		c.WithQuality(v)
+	}
+	if n := p.GetPageNumber(); n != 0 {
+		c.WithPage(rbc.PageNumber(n))
+	}
+	if name := p.GetLocation(); name != "" {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, err
+		}
+		c.WithLocation(loc)
+	}
+	if q := p.GetFilter(); q != "" {
+		filter, err := rbc.ParseQuery(q)
+		if err != nil {
+			return nil, err
+		}
+		c.WithFilter(filter)
+	}
+	return c, c.Validate()
+}
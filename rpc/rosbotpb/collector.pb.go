@@ -0,0 +1,1245 @@
+// Protocol buffer definitions of the collector, for consumers in other languages.
+//
+// The Go code is generated into 'rpc/rosbotpb' by `go generate ./rpc`.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: rosbot/v1/collector.proto
+
+package rosbotpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Rarity is the rarity of a legendary item.
+type Rarity int32
+
+const (
+	// RARITY_UNSPECIFIED defaults to RARITY_NON_ANCIENT in a parser configuration.
+	Rarity_RARITY_UNSPECIFIED Rarity = 0
+	Rarity_RARITY_NON_ANCIENT Rarity = 1
+	Rarity_RARITY_ANCIENT     Rarity = 2
+	Rarity_RARITY_PRIMAL      Rarity = 3
+)
+
+// Enum value maps for Rarity.
+var (
+	Rarity_name = map[int32]string{
+		0: "RARITY_UNSPECIFIED",
+		1: "RARITY_NON_ANCIENT",
+		2: "RARITY_ANCIENT",
+		3: "RARITY_PRIMAL",
+	}
+	Rarity_value = map[string]int32{
+		"RARITY_UNSPECIFIED": 0,
+		"RARITY_NON_ANCIENT": 1,
+		"RARITY_ANCIENT":     2,
+		"RARITY_PRIMAL":      3,
+	}
+)
+
+func (x Rarity) Enum() *Rarity {
+	p := new(Rarity)
+	*p = x
+	return p
+}
+
+func (x Rarity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Rarity) Descriptor() protoreflect.EnumDescriptor {
+	return file_rosbot_v1_collector_proto_enumTypes[0].Descriptor()
+}
+
+func (Rarity) Type() protoreflect.EnumType {
+	return &file_rosbot_v1_collector_proto_enumTypes[0]
+}
+
+func (x Rarity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Rarity.Descriptor instead.
+func (Rarity) EnumDescriptor() ([]byte, []int) {
+	return file_rosbot_v1_collector_proto_rawDescGZIP(), []int{0}
+}
+
+// Quality is the quality of a legendary item.
+type Quality int32
+
+const (
+	// QUALITY_UNSPECIFIED defaults to QUALITY_ALL in a parser configuration.
+	Quality_QUALITY_UNSPECIFIED Quality = 0
+	// QUALITY_ALL only applies to a parser configuration.
+	Quality_QUALITY_ALL    Quality = 1
+	Quality_QUALITY_NORMAL Quality = 2
+	Quality_QUALITY_SET    Quality = 3
+)
+
+// Enum value maps for Quality.
+var (
+	Quality_name = map[int32]string{
+		0: "QUALITY_UNSPECIFIED",
+		1: "QUALITY_ALL",
+		2: "QUALITY_NORMAL",
+		3: "QUALITY_SET",
+	}
+	Quality_value = map[string]int32{
+		"QUALITY_UNSPECIFIED": 0,
+		"QUALITY_ALL":         1,
+		"QUALITY_NORMAL":      2,
+		"QUALITY_SET":         3,
+	}
+)
+
+func (x Quality) Enum() *Quality {
+	p := new(Quality)
+	*p = x
+	return p
+}
+
+func (x Quality) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Quality) Descriptor() protoreflect.EnumDescriptor {
+	return file_rosbot_v1_collector_proto_enumTypes[1].Descriptor()
+}
+
+func (Quality) Type() protoreflect.EnumType {
+	return &file_rosbot_v1_collector_proto_enumTypes[1]
+}
+
+func (x Quality) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Quality.Descriptor instead.
+func (Quality) EnumDescriptor() ([]byte, []int) {
+	return file_rosbot_v1_collector_proto_rawDescGZIP(), []int{1}
+}
+
+// Destination is where the bot placed the item upon collection of it.
+type Destination int32
+
+const (
+	Destination_DESTINATION_UNSPECIFIED Destination = 0
+	Destination_DESTINATION_STASHED     Destination = 1
+	Destination_DESTINATION_SALVAGED    Destination = 2
+	Destination_DESTINATION_SOLD        Destination = 3
+	Destination_DESTINATION_UNKNOWN     Destination = 4
+)
+
+// Enum value maps for Destination.
+var (
+	Destination_name = map[int32]string{
+		0: "DESTINATION_UNSPECIFIED",
+		1: "DESTINATION_STASHED",
+		2: "DESTINATION_SALVAGED",
+		3: "DESTINATION_SOLD",
+		4: "DESTINATION_UNKNOWN",
+	}
+	Destination_value = map[string]int32{
+		"DESTINATION_UNSPECIFIED": 0,
+		"DESTINATION_STASHED":     1,
+		"DESTINATION_SALVAGED":    2,
+		"DESTINATION_SOLD":        3,
+		"DESTINATION_UNKNOWN":     4,
+	}
+)
+
+func (x Destination) Enum() *Destination {
+	p := new(Destination)
+	*p = x
+	return p
+}
+
+func (x Destination) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Destination) Descriptor() protoreflect.EnumDescriptor {
+	return file_rosbot_v1_collector_proto_enumTypes[2].Descriptor()
+}
+
+func (Destination) Type() protoreflect.EnumType {
+	return &file_rosbot_v1_collector_proto_enumTypes[2]
+}
+
+func (x Destination) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Destination.Descriptor instead.
+func (Destination) EnumDescriptor() ([]byte, []int) {
+	return file_rosbot_v1_collector_proto_rawDescGZIP(), []int{2}
+}
+
+// LegendaryItem is a Diablo III legendary item.
+type LegendaryItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// id identifies the item across requests, whatever the parsing configuration; identical items
+	// of the same minute are told apart by their order on the page.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// index is the position of the item within the server update.
+	Index        int32       `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	Name         string      `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Quality      Quality     `protobuf:"varint,4,opt,name=quality,proto3,enum=rosbot.v1.Quality" json:"quality,omitempty"`
+	Rarity       Rarity      `protobuf:"varint,5,opt,name=rarity,proto3,enum=rosbot.v1.Rarity" json:"rarity,omitempty"`
+	Destination  Destination `protobuf:"varint,6,opt,name=destination,proto3,enum=rosbot.v1.Destination" json:"destination,omitempty"`
+	IsIdentified bool        `protobuf:"varint,7,opt,name=is_identified,json=isIdentified,proto3" json:"is_identified,omitempty"`
+	Stats        string      `protobuf:"bytes,8,opt,name=stats,proto3" json:"stats,omitempty"`
+	// bot_name is the name of the hero which collected the item.
+	BotName string `protobuf:"bytes,9,opt,name=bot_name,json=botName,proto3" json:"bot_name,omitempty"`
+}
+
+func (x *LegendaryItem) Reset() {
+	*x = LegendaryItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rosbot_v1_collector_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LegendaryItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LegendaryItem) ProtoMessage() {}
+
+func (x *LegendaryItem) ProtoReflect() protoreflect.Message {
+	mi := &file_rosbot_v1_collector_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LegendaryItem.ProtoReflect.Descriptor instead.
+func (*LegendaryItem) Descriptor() ([]byte, []int) {
+	return file_rosbot_v1_collector_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LegendaryItem) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *LegendaryItem) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *LegendaryItem) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LegendaryItem) GetQuality() Quality {
+	if x != nil {
+		return x.Quality
+	}
+	return Quality_QUALITY_UNSPECIFIED
+}
+
+func (x *LegendaryItem) GetRarity() Rarity {
+	if x != nil {
+		return x.Rarity
+	}
+	return Rarity_RARITY_UNSPECIFIED
+}
+
+func (x *LegendaryItem) GetDestination() Destination {
+	if x != nil {
+		return x.Destination
+	}
+	return Destination_DESTINATION_UNSPECIFIED
+}
+
+func (x *LegendaryItem) GetIsIdentified() bool {
+	if x != nil {
+		return x.IsIdentified
+	}
+	return false
+}
+
+func (x *LegendaryItem) GetStats() string {
+	if x != nil {
+		return x.Stats
+	}
+	return ""
+}
+
+func (x *LegendaryItem) GetBotName() string {
+	if x != nil {
+		return x.BotName
+	}
+	return ""
+}
+
+// ParseWarning is a non-fatal anomaly encountered while parsing a server update.
+type ParseWarning struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Field   string `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Raw     string `protobuf:"bytes,2,opt,name=raw,proto3" json:"raw,omitempty"`
+	Message string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *ParseWarning) Reset() {
+	*x = ParseWarning{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rosbot_v1_collector_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ParseWarning) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParseWarning) ProtoMessage() {}
+
+func (x *ParseWarning) ProtoReflect() protoreflect.Message {
+	mi := &file_rosbot_v1_collector_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParseWarning.ProtoReflect.Descriptor instead.
+func (*ParseWarning) Descriptor() ([]byte, []int) {
+	return file_rosbot_v1_collector_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ParseWarning) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *ParseWarning) GetRaw() string {
+	if x != nil {
+		return x.Raw
+	}
+	return ""
+}
+
+func (x *ParseWarning) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// ServerUpdate is a Ros-Bot server update.
+type ServerUpdate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// id identifies the update across requests made with the same parsing configuration; identical
+	// updates of the same minute are told apart by their order on the page.
+	Id    string           `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Items []*LegendaryItem `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	// server_timestamp is the absolute date displayed by the site; minute resolution.
+	ServerTimestamp *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=server_timestamp,json=serverTimestamp,proto3" json:"server_timestamp,omitempty"`
+	// derived_timestamp is the response 'Date' header minus the relative time.
+	DerivedTimestamp *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=derived_timestamp,json=derivedTimestamp,proto3" json:"derived_timestamp,omitempty"`
+	// utc_offset is the offset of the timezone the site rendered server_timestamp in.
+	UtcOffset       *durationpb.Duration `protobuf:"bytes,5,opt,name=utc_offset,json=utcOffset,proto3" json:"utc_offset,omitempty"`
+	RawTimestamp    string               `protobuf:"bytes,6,opt,name=raw_timestamp,json=rawTimestamp,proto3" json:"raw_timestamp,omitempty"`
+	RawRelativeTime string               `protobuf:"bytes,7,opt,name=raw_relative_time,json=rawRelativeTime,proto3" json:"raw_relative_time,omitempty"`
+	Warnings        []*ParseWarning      `protobuf:"bytes,8,rep,name=warnings,proto3" json:"warnings,omitempty"`
+}
+
+func (x *ServerUpdate) Reset() {
+	*x = ServerUpdate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rosbot_v1_collector_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ServerUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerUpdate) ProtoMessage() {}
+
+func (x *ServerUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_rosbot_v1_collector_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerUpdate.ProtoReflect.Descriptor instead.
+func (*ServerUpdate) Descriptor() ([]byte, []int) {
+	return file_rosbot_v1_collector_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ServerUpdate) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ServerUpdate) GetItems() []*LegendaryItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ServerUpdate) GetServerTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ServerTimestamp
+	}
+	return nil
+}
+
+func (x *ServerUpdate) GetDerivedTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DerivedTimestamp
+	}
+	return nil
+}
+
+func (x *ServerUpdate) GetUtcOffset() *durationpb.Duration {
+	if x != nil {
+		return x.UtcOffset
+	}
+	return nil
+}
+
+func (x *ServerUpdate) GetRawTimestamp() string {
+	if x != nil {
+		return x.RawTimestamp
+	}
+	return ""
+}
+
+func (x *ServerUpdate) GetRawRelativeTime() string {
+	if x != nil {
+		return x.RawRelativeTime
+	}
+	return ""
+}
+
+func (x *ServerUpdate) GetWarnings() []*ParseWarning {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+// ParserConfig is the parsing configuration.
+type ParserConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// destinations to parse; none means all of them.
+	Destinations []Destination `protobuf:"varint,1,rep,packed,name=destinations,proto3,enum=rosbot.v1.Destination" json:"destinations,omitempty"`
+	// rarity_level is the minimum rarity to parse.
+	RarityLevel Rarity  `protobuf:"varint,2,opt,name=rarity_level,json=rarityLevel,proto3,enum=rosbot.v1.Rarity" json:"rarity_level,omitempty"`
+	Quality     Quality `protobuf:"varint,3,opt,name=quality,proto3,enum=rosbot.v1.Quality" json:"quality,omitempty"`
+	// page_number is the one-based page to parse; defaults to the first (newest) page.
+	PageNumber int32 `protobuf:"varint,4,opt,name=page_number,json=pageNumber,proto3" json:"page_number,omitempty"`
+	// location is the IANA name of the timezone in which the site renders timestamps, i.e.
+	// "Europe/Paris"; defaults to the server's.
+	Location string `protobuf:"bytes,5,opt,name=location,proto3" json:"location,omitempty"`
+	// filter is a query applied on top of the other criteria, i.e.
+	// `rarity>=ancient and name~"tyrael"`.
+	Filter string `protobuf:"bytes,6,opt,name=filter,proto3" json:"filter,omitempty"`
+}
+
+func (x *ParserConfig) Reset() {
+	*x = ParserConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rosbot_v1_collector_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ParserConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParserConfig) ProtoMessage() {}
+
+func (x *ParserConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_rosbot_v1_collector_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParserConfig.ProtoReflect.Descriptor instead.
+func (*ParserConfig) Descriptor() ([]byte, []int) {
+	return file_rosbot_v1_collector_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ParserConfig) GetDestinations() []Destination {
+	if x != nil {
+		return x.Destinations
+	}
+	return nil
+}
+
+func (x *ParserConfig) GetRarityLevel() Rarity {
+	if x != nil {
+		return x.RarityLevel
+	}
+	return Rarity_RARITY_UNSPECIFIED
+}
+
+func (x *ParserConfig) GetQuality() Quality {
+	if x != nil {
+		return x.Quality
+	}
+	return Quality_QUALITY_UNSPECIFIED
+}
+
+func (x *ParserConfig) GetPageNumber() int32 {
+	if x != nil {
+		return x.PageNumber
+	}
+	return 0
+}
+
+func (x *ParserConfig) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *ParserConfig) GetFilter() string {
+	if x != nil {
+		return x.Filter
+	}
+	return ""
+}
+
+// PageInfo is the pagination metadata of the activity page.
+type PageInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CurrentPage  int32 `protobuf:"varint,1,opt,name=current_page,json=currentPage,proto3" json:"current_page,omitempty"`
+	PageSize     int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	FirstEntry   int32 `protobuf:"varint,3,opt,name=first_entry,json=firstEntry,proto3" json:"first_entry,omitempty"`
+	LastEntry    int32 `protobuf:"varint,4,opt,name=last_entry,json=lastEntry,proto3" json:"last_entry,omitempty"`
+	TotalEntries int32 `protobuf:"varint,5,opt,name=total_entries,json=totalEntries,proto3" json:"total_entries,omitempty"`
+	TotalPages   int32 `protobuf:"varint,6,opt,name=total_pages,json=totalPages,proto3" json:"total_pages,omitempty"`
+	HasNext      bool  `protobuf:"varint,7,opt,name=has_next,json=hasNext,proto3" json:"has_next,omitempty"`
+	HasPrevious  bool  `protobuf:"varint,8,opt,name=has_previous,json=hasPrevious,proto3" json:"has_previous,omitempty"`
+}
+
+func (x *PageInfo) Reset() {
+	*x = PageInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rosbot_v1_collector_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PageInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PageInfo) ProtoMessage() {}
+
+func (x *PageInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_rosbot_v1_collector_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PageInfo.ProtoReflect.Descriptor instead.
+func (*PageInfo) Descriptor() ([]byte, []int) {
+	return file_rosbot_v1_collector_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PageInfo) GetCurrentPage() int32 {
+	if x != nil {
+		return x.CurrentPage
+	}
+	return 0
+}
+
+func (x *PageInfo) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *PageInfo) GetFirstEntry() int32 {
+	if x != nil {
+		return x.FirstEntry
+	}
+	return 0
+}
+
+func (x *PageInfo) GetLastEntry() int32 {
+	if x != nil {
+		return x.LastEntry
+	}
+	return 0
+}
+
+func (x *PageInfo) GetTotalEntries() int32 {
+	if x != nil {
+		return x.TotalEntries
+	}
+	return 0
+}
+
+func (x *PageInfo) GetTotalPages() int32 {
+	if x != nil {
+		return x.TotalPages
+	}
+	return 0
+}
+
+func (x *PageInfo) GetHasNext() bool {
+	if x != nil {
+		return x.HasNext
+	}
+	return false
+}
+
+func (x *PageInfo) GetHasPrevious() bool {
+	if x != nil {
+		return x.HasPrevious
+	}
+	return false
+}
+
+type ParseRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Config *ParserConfig `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+}
+
+func (x *ParseRequest) Reset() {
+	*x = ParseRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rosbot_v1_collector_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ParseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParseRequest) ProtoMessage() {}
+
+func (x *ParseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rosbot_v1_collector_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParseRequest.ProtoReflect.Descriptor instead.
+func (*ParseRequest) Descriptor() ([]byte, []int) {
+	return file_rosbot_v1_collector_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ParseRequest) GetConfig() *ParserConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+type ParseResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Updates  []*ServerUpdate `protobuf:"bytes,1,rep,name=updates,proto3" json:"updates,omitempty"`
+	PageInfo *PageInfo       `protobuf:"bytes,2,opt,name=page_info,json=pageInfo,proto3" json:"page_info,omitempty"`
+}
+
+func (x *ParseResponse) Reset() {
+	*x = ParseResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rosbot_v1_collector_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ParseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParseResponse) ProtoMessage() {}
+
+func (x *ParseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rosbot_v1_collector_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParseResponse.ProtoReflect.Descriptor instead.
+func (*ParseResponse) Descriptor() ([]byte, []int) {
+	return file_rosbot_v1_collector_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ParseResponse) GetUpdates() []*ServerUpdate {
+	if x != nil {
+		return x.Updates
+	}
+	return nil
+}
+
+func (x *ParseResponse) GetPageInfo() *PageInfo {
+	if x != nil {
+		return x.PageInfo
+	}
+	return nil
+}
+
+type WatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// config applies to every poll; its page_number is ignored.
+	Config *ParserConfig `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	// interval is the delay between two polls; raised to the server's minimum.
+	Interval *durationpb.Duration `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	// skip_existing does not stream the updates already displayed on the first poll.
+	SkipExisting bool `protobuf:"varint,3,opt,name=skip_existing,json=skipExisting,proto3" json:"skip_existing,omitempty"`
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rosbot_v1_collector_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rosbot_v1_collector_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_rosbot_v1_collector_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *WatchRequest) GetConfig() *ParserConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *WatchRequest) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+func (x *WatchRequest) GetSkipExisting() bool {
+	if x != nil {
+		return x.SkipExisting
+	}
+	return false
+}
+
+type WatchResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Update *ServerUpdate `protobuf:"bytes,1,opt,name=update,proto3" json:"update,omitempty"`
+}
+
+func (x *WatchResponse) Reset() {
+	*x = WatchResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rosbot_v1_collector_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchResponse) ProtoMessage() {}
+
+func (x *WatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rosbot_v1_collector_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchResponse.ProtoReflect.Descriptor instead.
+func (*WatchResponse) Descriptor() ([]byte, []int) {
+	return file_rosbot_v1_collector_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *WatchResponse) GetUpdate() *ServerUpdate {
+	if x != nil {
+		return x.Update
+	}
+	return nil
+}
+
+var File_rosbot_v1_collector_proto protoreflect.FileDescriptor
+
+var file_rosbot_v1_collector_proto_rawDesc = []byte{
+	0x0a, 0x19, 0x72, 0x6f, 0x73, 0x62, 0x6f, 0x74, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x6f, 0x6c, 0x6c,
+	0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09, 0x72, 0x6f, 0x73,
+	0x62, 0x6f, 0x74, 0x2e, 0x76, 0x31, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xb2, 0x02, 0x0a, 0x0d, 0x4c, 0x65, 0x67, 0x65,
+	0x6e, 0x64, 0x61, 0x72, 0x79, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64,
+	0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x2c, 0x0a, 0x07, 0x71, 0x75, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x72, 0x6f, 0x73, 0x62, 0x6f, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x51, 0x75, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x07, 0x71, 0x75, 0x61, 0x6c, 0x69, 0x74,
+	0x79, 0x12, 0x29, 0x0a, 0x06, 0x72, 0x61, 0x72, 0x69, 0x74, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x11, 0x2e, 0x72, 0x6f, 0x73, 0x62, 0x6f, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x61,
+	0x72, 0x69, 0x74, 0x79, 0x52, 0x06, 0x72, 0x61, 0x72, 0x69, 0x74, 0x79, 0x12, 0x38, 0x0a, 0x0b,
+	0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x16, 0x2e, 0x72, 0x6f, 0x73, 0x62, 0x6f, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65,
+	0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x74, 0x69,
+	0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x69, 0x73, 0x5f, 0x69, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x69,
+	0x73, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x73,
+	0x74, 0x61, 0x74, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74,
+	0x73, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x6f, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x6f, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x50, 0x0a, 0x0c,
+	0x50, 0x61, 0x72, 0x73, 0x65, 0x57, 0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x14, 0x0a, 0x05,
+	0x66, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x66, 0x69, 0x65,
+	0x6c, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x61, 0x77, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x72, 0x61, 0x77, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x9e,
+	0x03, 0x0a, 0x0c, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x2e, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18,
+	0x2e, 0x72, 0x6f, 0x73, 0x62, 0x6f, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x65, 0x67, 0x65, 0x6e,
+	0x64, 0x61, 0x72, 0x79, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x12,
+	0x45, 0x0a, 0x10, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x47, 0x0a, 0x11, 0x64, 0x65, 0x72, 0x69, 0x76, 0x65,
+	0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x10, 0x64,
+	0x65, 0x72, 0x69, 0x76, 0x65, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12,
+	0x38, 0x0a, 0x0a, 0x75, 0x74, 0x63, 0x5f, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09,
+	0x75, 0x74, 0x63, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x72, 0x61, 0x77,
+	0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0c, 0x72, 0x61, 0x77, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x2a,
+	0x0a, 0x11, 0x72, 0x61, 0x77, 0x5f, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x74,
+	0x69, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x72, 0x61, 0x77, 0x52, 0x65,
+	0x6c, 0x61, 0x74, 0x69, 0x76, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x33, 0x0a, 0x08, 0x77, 0x61,
+	0x72, 0x6e, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x72,
+	0x6f, 0x73, 0x62, 0x6f, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x73, 0x65, 0x57, 0x61,
+	0x72, 0x6e, 0x69, 0x6e, 0x67, 0x52, 0x08, 0x77, 0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x73, 0x22,
+	0x83, 0x02, 0x0a, 0x0c, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x12, 0x3a, 0x0a, 0x0c, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x72, 0x6f, 0x73, 0x62, 0x6f, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0c,
+	0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x34, 0x0a, 0x0c,
+	0x72, 0x61, 0x72, 0x69, 0x74, 0x79, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x11, 0x2e, 0x72, 0x6f, 0x73, 0x62, 0x6f, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x52,
+	0x61, 0x72, 0x69, 0x74, 0x79, 0x52, 0x0b, 0x72, 0x61, 0x72, 0x69, 0x74, 0x79, 0x4c, 0x65, 0x76,
+	0x65, 0x6c, 0x12, 0x2c, 0x0a, 0x07, 0x71, 0x75, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x72, 0x6f, 0x73, 0x62, 0x6f, 0x74, 0x2e, 0x76, 0x31, 0x2e,
+	0x51, 0x75, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x07, 0x71, 0x75, 0x61, 0x6c, 0x69, 0x74, 0x79,
+	0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x70, 0x61, 0x67, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65,
+	0x72, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a,
+	0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66,
+	0x69, 0x6c, 0x74, 0x65, 0x72, 0x22, 0x8e, 0x02, 0x0a, 0x08, 0x50, 0x61, 0x67, 0x65, 0x49, 0x6e,
+	0x66, 0x6f, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x70, 0x61,
+	0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e,
+	0x74, 0x50, 0x61, 0x67, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69,
+	0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69,
+	0x7a, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f, 0x65, 0x6e, 0x74, 0x72,
+	0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x66, 0x69, 0x72, 0x73, 0x74, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x65, 0x6e, 0x74, 0x72,
+	0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x23, 0x0a, 0x0d, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x65, 0x6e, 0x74, 0x72,
+	0x69, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x5f, 0x70, 0x61, 0x67, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x74, 0x6f,
+	0x74, 0x61, 0x6c, 0x50, 0x61, 0x67, 0x65, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x68, 0x61, 0x73, 0x5f,
+	0x6e, 0x65, 0x78, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x68, 0x61, 0x73, 0x4e,
+	0x65, 0x78, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x68, 0x61, 0x73, 0x5f, 0x70, 0x72, 0x65, 0x76, 0x69,
+	0x6f, 0x75, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x68, 0x61, 0x73, 0x50, 0x72,
+	0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x22, 0x3f, 0x0a, 0x0c, 0x50, 0x61, 0x72, 0x73, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2f, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x72, 0x6f, 0x73, 0x62, 0x6f, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52,
+	0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0x74, 0x0a, 0x0d, 0x50, 0x61, 0x72, 0x73, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x07, 0x75, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x72, 0x6f, 0x73, 0x62,
+	0x6f, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x52, 0x07, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x12, 0x30, 0x0a, 0x09, 0x70,
+	0x61, 0x67, 0x65, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13,
+	0x2e, 0x72, 0x6f, 0x73, 0x62, 0x6f, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x67, 0x65, 0x49,
+	0x6e, 0x66, 0x6f, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x22, 0x9b, 0x01,
+	0x0a, 0x0c, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2f,
+	0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17,
+	0x2e, 0x72, 0x6f, 0x73, 0x62, 0x6f, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x73, 0x65,
+	0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12,
+	0x35, 0x0a, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x69, 0x6e,
+	0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x6b, 0x69, 0x70, 0x5f, 0x65,
+	0x78, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x73,
+	0x6b, 0x69, 0x70, 0x45, 0x78, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x67, 0x22, 0x40, 0x0a, 0x0d, 0x57,
+	0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x06,
+	0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x72,
+	0x6f, 0x73, 0x62, 0x6f, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x06, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x2a, 0x5f, 0x0a,
+	0x06, 0x52, 0x61, 0x72, 0x69, 0x74, 0x79, 0x12, 0x16, 0x0a, 0x12, 0x52, 0x41, 0x52, 0x49, 0x54,
+	0x59, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12,
+	0x16, 0x0a, 0x12, 0x52, 0x41, 0x52, 0x49, 0x54, 0x59, 0x5f, 0x4e, 0x4f, 0x4e, 0x5f, 0x41, 0x4e,
+	0x43, 0x49, 0x45, 0x4e, 0x54, 0x10, 0x01, 0x12, 0x12, 0x0a, 0x0e, 0x52, 0x41, 0x52, 0x49, 0x54,
+	0x59, 0x5f, 0x41, 0x4e, 0x43, 0x49, 0x45, 0x4e, 0x54, 0x10, 0x02, 0x12, 0x11, 0x0a, 0x0d, 0x52,
+	0x41, 0x52, 0x49, 0x54, 0x59, 0x5f, 0x50, 0x52, 0x49, 0x4d, 0x41, 0x4c, 0x10, 0x03, 0x2a, 0x58,
+	0x0a, 0x07, 0x51, 0x75, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x17, 0x0a, 0x13, 0x51, 0x55, 0x41,
+	0x4c, 0x49, 0x54, 0x59, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44,
+	0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x51, 0x55, 0x41, 0x4c, 0x49, 0x54, 0x59, 0x5f, 0x41, 0x4c,
+	0x4c, 0x10, 0x01, 0x12, 0x12, 0x0a, 0x0e, 0x51, 0x55, 0x41, 0x4c, 0x49, 0x54, 0x59, 0x5f, 0x4e,
+	0x4f, 0x52, 0x4d, 0x41, 0x4c, 0x10, 0x02, 0x12, 0x0f, 0x0a, 0x0b, 0x51, 0x55, 0x41, 0x4c, 0x49,
+	0x54, 0x59, 0x5f, 0x53, 0x45, 0x54, 0x10, 0x03, 0x2a, 0x8c, 0x01, 0x0a, 0x0b, 0x44, 0x65, 0x73,
+	0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1b, 0x0a, 0x17, 0x44, 0x45, 0x53, 0x54,
+	0x49, 0x4e, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46,
+	0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x17, 0x0a, 0x13, 0x44, 0x45, 0x53, 0x54, 0x49, 0x4e, 0x41,
+	0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x53, 0x48, 0x45, 0x44, 0x10, 0x01, 0x12, 0x18,
+	0x0a, 0x14, 0x44, 0x45, 0x53, 0x54, 0x49, 0x4e, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x41,
+	0x4c, 0x56, 0x41, 0x47, 0x45, 0x44, 0x10, 0x02, 0x12, 0x14, 0x0a, 0x10, 0x44, 0x45, 0x53, 0x54,
+	0x49, 0x4e, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x4f, 0x4c, 0x44, 0x10, 0x03, 0x12, 0x17,
+	0x0a, 0x13, 0x44, 0x45, 0x53, 0x54, 0x49, 0x4e, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x55, 0x4e,
+	0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x04, 0x32, 0x8c, 0x01, 0x0a, 0x10, 0x43, 0x6f, 0x6c, 0x6c,
+	0x65, 0x63, 0x74, 0x6f, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3a, 0x0a, 0x05,
+	0x50, 0x61, 0x72, 0x73, 0x65, 0x12, 0x17, 0x2e, 0x72, 0x6f, 0x73, 0x62, 0x6f, 0x74, 0x2e, 0x76,
+	0x31, 0x2e, 0x50, 0x61, 0x72, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18,
+	0x2e, 0x72, 0x6f, 0x73, 0x62, 0x6f, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x73, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3c, 0x0a, 0x05, 0x57, 0x61, 0x74, 0x63,
+	0x68, 0x12, 0x17, 0x2e, 0x72, 0x6f, 0x73, 0x62, 0x6f, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x72, 0x6f, 0x73,
+	0x62, 0x6f, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x42, 0x38, 0x5a, 0x36, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x61, 0x78, 0x7a, 0x61, 0x6c, 0x65, 0x73, 0x6b, 0x69, 0x2f,
+	0x67, 0x6f, 0x2d, 0x72, 0x6f, 0x73, 0x62, 0x6f, 0x74, 0x2d, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x72, 0x6f, 0x73, 0x62, 0x6f, 0x74, 0x70, 0x62,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_rosbot_v1_collector_proto_rawDescOnce sync.Once
+	file_rosbot_v1_collector_proto_rawDescData = file_rosbot_v1_collector_proto_rawDesc
+)
+
+func file_rosbot_v1_collector_proto_rawDescGZIP() []byte {
+	file_rosbot_v1_collector_proto_rawDescOnce.Do(func() {
+		file_rosbot_v1_collector_proto_rawDescData = protoimpl.X.CompressGZIP(file_rosbot_v1_collector_proto_rawDescData)
+	})
+	return file_rosbot_v1_collector_proto_rawDescData
+}
+
+var file_rosbot_v1_collector_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_rosbot_v1_collector_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_rosbot_v1_collector_proto_goTypes = []any{
+	(Rarity)(0),                   // 0: rosbot.v1.Rarity
+	(Quality)(0),                  // 1: rosbot.v1.Quality
+	(Destination)(0),              // 2: rosbot.v1.Destination
+	(*LegendaryItem)(nil),         // 3: rosbot.v1.LegendaryItem
+	(*ParseWarning)(nil),          // 4: rosbot.v1.ParseWarning
+	(*ServerUpdate)(nil),          // 5: rosbot.v1.ServerUpdate
+	(*ParserConfig)(nil),          // 6: rosbot.v1.ParserConfig
+	(*PageInfo)(nil),              // 7: rosbot.v1.PageInfo
+	(*ParseRequest)(nil),          // 8: rosbot.v1.ParseRequest
+	(*ParseResponse)(nil),         // 9: rosbot.v1.ParseResponse
+	(*WatchRequest)(nil),          // 10: rosbot.v1.WatchRequest
+	(*WatchResponse)(nil),         // 11: rosbot.v1.WatchResponse
+	(*timestamppb.Timestamp)(nil), // 12: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),   // 13: google.protobuf.Duration
+}
+var file_rosbot_v1_collector_proto_depIdxs = []int32{
+	1,  // 0: rosbot.v1.LegendaryItem.quality:type_name -> rosbot.v1.Quality
+	0,  // 1: rosbot.v1.LegendaryItem.rarity:type_name -> rosbot.v1.Rarity
+	2,  // 2: rosbot.v1.LegendaryItem.destination:type_name -> rosbot.v1.Destination
+	3,  // 3: rosbot.v1.ServerUpdate.items:type_name -> rosbot.v1.LegendaryItem
+	12, // 4: rosbot.v1.ServerUpdate.server_timestamp:type_name -> google.protobuf.Timestamp
+	12, // 5: rosbot.v1.ServerUpdate.derived_timestamp:type_name -> google.protobuf.Timestamp
+	13, // 6: rosbot.v1.ServerUpdate.utc_offset:type_name -> google.protobuf.Duration
+	4,  // 7: rosbot.v1.ServerUpdate.warnings:type_name -> rosbot.v1.ParseWarning
+	2,  // 8: rosbot.v1.ParserConfig.destinations:type_name -> rosbot.v1.Destination
+	0,  // 9: rosbot.v1.ParserConfig.rarity_level:type_name -> rosbot.v1.Rarity
+	1,  // 10: rosbot.v1.ParserConfig.quality:type_name -> rosbot.v1.Quality
+	6,  // 11: rosbot.v1.ParseRequest.config:type_name -> rosbot.v1.ParserConfig
+	5,  // 12: rosbot.v1.ParseResponse.updates:type_name -> rosbot.v1.ServerUpdate
+	7,  // 13: rosbot.v1.ParseResponse.page_info:type_name -> rosbot.v1.PageInfo
+	6,  // 14: rosbot.v1.WatchRequest.config:type_name -> rosbot.v1.ParserConfig
+	13, // 15: rosbot.v1.WatchRequest.interval:type_name -> google.protobuf.Duration
+	5,  // 16: rosbot.v1.WatchResponse.update:type_name -> rosbot.v1.ServerUpdate
+	8,  // 17: rosbot.v1.CollectorService.Parse:input_type -> rosbot.v1.ParseRequest
+	10, // 18: rosbot.v1.CollectorService.Watch:input_type -> rosbot.v1.WatchRequest
+	9,  // 19: rosbot.v1.CollectorService.Parse:output_type -> rosbot.v1.ParseResponse
+	11, // 20: rosbot.v1.CollectorService.Watch:output_type -> rosbot.v1.WatchResponse
+	19, // [19:21] is the sub-list for method output_type
+	17, // [17:19] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
+}
+
+func init() { file_rosbot_v1_collector_proto_init() }
+func file_rosbot_v1_collector_proto_init() {
+	if File_rosbot_v1_collector_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_rosbot_v1_collector_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*LegendaryItem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rosbot_v1_collector_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*ParseWarning); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rosbot_v1_collector_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*ServerUpdate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rosbot_v1_collector_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*ParserConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rosbot_v1_collector_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*PageInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rosbot_v1_collector_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*ParseRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rosbot_v1_collector_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*ParseResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rosbot_v1_collector_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*WatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rosbot_v1_collector_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*WatchResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_rosbot_v1_collector_proto_rawDesc,
+			NumEnums:      3,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_rosbot_v1_collector_proto_goTypes,
+		DependencyIndexes: file_rosbot_v1_collector_proto_depIdxs,
+		EnumInfos:         file_rosbot_v1_collector_proto_enumTypes,
+		MessageInfos:      file_rosbot_v1_collector_proto_msgTypes,
+	}.Build()
+	File_rosbot_v1_collector_proto = out.File
+	file_rosbot_v1_collector_proto_rawDesc = nil
+	file_rosbot_v1_collector_proto_goTypes = nil
+	file_rosbot_v1_collector_proto_depIdxs = nil
+}
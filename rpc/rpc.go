@@ -0,0 +1,133 @@
+// Package rpc serves a `rosbotcollector.Client` over gRPC, so that services in other languages
+// can consume the collector without re-implementing the scraper.
+//
+// The service is defined in 'proto/rosbot/v1/collector.proto'; its Go code lives in
+// 'rpc/rosbotpb'.
+//
+//	s := grpc.NewServer()
+//	rosbotpb.RegisterCollectorServiceServer(s, rpc.NewServer(client))
+//	s.Serve(lis)
+package rpc
+
+//go:generate sh -c "cd .. && buf generate proto"
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	rbc "github.com/maxzaleski/go-rosbot-collector"
+	"github.com/maxzaleski/go-rosbot-collector/rpc/rosbotpb"
+)
+
+// DefaultMinInterval is the minimum polling interval of `Watch` calls.
+const DefaultMinInterval = time.Minute
+
+type (
+	// Server implements `rosbotpb.CollectorServiceServer`.
+	Server struct {
+		rosbotpb.UnimplementedCollectorServiceServer
+
+		client      rbc.Client
+		minInterval time.Duration
+		onError     func(err error)
+	}
+
+	// Option configures a `Server`.
+	Option func(s *Server)
+)
+
+// WithMinInterval sets the minimum polling interval of `Watch` calls, which protects the site,
+// and the account, from clients polling too often. Defaults to `DefaultMinInterval`.
+func WithMinInterval(d time.Duration) Option {
+	return func(s *Server) {
+		s.minInterval = d
+	}
+}
+
+// WithErrorHandler sets the function called with every failed poll of a `Watch` call; the call
+// goes on, retrying with a backoff.
+func WithErrorHandler(f func(err error)) Option {
+	return func(s *Server) {
+		s.onError = f
+	}
+}
+
+// NewServer returns a new instance of `rpc.Server`, parsing through the given client.
+func NewServer(c rbc.Client, opts ...Option) *Server {
+	s := &Server{client: c, minInterval: DefaultMinInterval}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Parse implements `rosbotpb.CollectorServiceServer`.
+func (s *Server) Parse(ctx context.Context, req *rosbotpb.ParseRequest) (*rosbotpb.ParseResponse, error) {
+	config, err := ConfigFromProto(req.GetConfig())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	page, err := s.client.ParsePageWithConfig(ctx, config)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	res := &rosbotpb.ParseResponse{
+		Updates:  make([]*rosbotpb.ServerUpdate, 0, len(page.Updates)),
+		PageInfo: PageInfoToProto(page.Info),
+	}
+	for _, u := range page.Updates {
+		res.Updates = append(res.Updates, UpdateToProto(u))
+	}
+	return res, nil
+}
+
+// Watch implements `rosbotpb.CollectorServiceServer`. Every call polls on its own.
+func (s *Server) Watch(req *rosbotpb.WatchRequest, stream rosbotpb.CollectorService_WatchServer) error {
+	config, err := ConfigFromProto(req.GetConfig())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	config.WithPage(rbc.FirstPage)
+
+	cc := rbc.NewCollectorConfig()
+	cc.Parser = config
+	if d := req.GetInterval().AsDuration(); d > 0 {
+		cc.Interval = d
+	}
+	if cc.Interval < s.minInterval {
+		cc.Interval = s.minInterval
+	}
+	// The default jitter would be out of proportion with short intervals.
+	if cc.Jitter > cc.Interval/10 {
+		cc.Jitter = cc.Interval / 10
+	}
+	cc.SkipExisting = req.GetSkipExisting()
+	cc.OnError = s.onError
+
+	collector := rbc.NewCollector(s.client, cc)
+	collector.Handle(func(_ context.Context, u *rbc.ServerUpdate) error {
+		return stream.Send(&rosbotpb.WatchResponse{Update: UpdateToProto(u)})
+	})
+	return status.FromContextError(collector.Run(stream.Context())).Err()
+}
+
+// statusError maps the errors of the client onto gRPC status codes.
+func statusError(err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	case errors.Is(err, rbc.ErrBadCredentials):
+		code = codes.Unauthenticated
+	case errors.Is(err, rbc.ErrInvalidPage):
+		code = codes.InvalidArgument
+	case errors.Is(err, rbc.ErrUnexpectedStatus), errors.Is(err, rbc.ErrCookiesRefresh):
+		code = codes.Unavailable
+	}
+	return status.Error(code, err.Error())
+}
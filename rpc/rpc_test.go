@@ -0,0 +1,174 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	rbc "github.com/maxzaleski/go-rosbot-collector"
+	"github.com/maxzaleski/go-rosbot-collector/rpc/rosbotpb"
+	"github.com/maxzaleski/go-rosbot-collector/store/storetest"
+)
+
+type fakeClient struct {
+	rbc.Client
+	mu      sync.Mutex
+	updates []*rbc.ServerUpdate
+	err     error
+	configs []*rbc.ParserConfig
+}
+
+func (c *fakeClient) ParseWithConfig(_ context.Context, config *rbc.ParserConfig) ([]*rbc.ServerUpdate, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.configs = append(c.configs, config)
+	return c.updates, c.err
+}
+
+func (c *fakeClient) ParsePageWithConfig(ctx context.Context, config *rbc.ParserConfig) (*rbc.ActivityPage, error) {
+	updates, err := c.ParseWithConfig(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return &rbc.ActivityPage{Updates: updates, Info: &rbc.PageInfo{CurrentPage: config.PageNumber, TotalPages: 3, HasNext: true}}, nil
+}
+
+// dial serves the server in-process, and returns a client of it.
+func dial(t *testing.T, s *Server) rosbotpb.CollectorServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	gs := grpc.NewServer()
+	rosbotpb.RegisterCollectorServiceServer(gs, s)
+	go func() { _ = gs.Serve(lis) }()
+	t.Cleanup(gs.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return rosbotpb.NewCollectorServiceClient(conn)
+}
+
+func TestServer_Parse(t *testing.T) {
+	c := &fakeClient{updates: storetest.Updates()}
+	client := dial(t, NewServer(c))
+
+	res, err := client.Parse(context.Background(), &rosbotpb.ParseRequest{Config: &rosbotpb.ParserConfig{
+		Destinations: []rosbotpb.Destination{rosbotpb.Destination_DESTINATION_STASHED},
+		RarityLevel:  rosbotpb.Rarity_RARITY_ANCIENT,
+		PageNumber:   2,
+		Location:     "Europe/Paris",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.GetUpdates()) != 3 || res.GetPageInfo().GetCurrentPage() != 2 || !res.GetPageInfo().GetHasNext() {
+		t.Errorf("Parse() = %v", res)
+	}
+	got := c.configs[0]
+	if got.RarityLevel != rbc.RarityAncient || got.Quality != rbc.QualityAll || got.PageNumber != 2 ||
+		!reflect.DeepEqual(got.Destinations, []rbc.Destination{rbc.DestinationStashed}) || got.Location.String() != "Europe/Paris" {
+		t.Errorf("config = %+v", got)
+	}
+}
+
+func TestServer_Parse_errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		config   *rosbotpb.ParserConfig
+		wantCode codes.Code
+	}{
+		{name: "invalid rarity", config: &rosbotpb.ParserConfig{RarityLevel: 42}, wantCode: codes.InvalidArgument},
+		{name: "unknown destination", config: &rosbotpb.ParserConfig{Destinations: []rosbotpb.Destination{rosbotpb.Destination_DESTINATION_UNKNOWN}}, wantCode: codes.InvalidArgument},
+		{name: "invalid page", config: &rosbotpb.ParserConfig{PageNumber: -1}, wantCode: codes.InvalidArgument},
+		{name: "invalid filter", config: &rosbotpb.ParserConfig{Filter: "rarity >"}, wantCode: codes.InvalidArgument},
+		{name: "invalid location", config: &rosbotpb.ParserConfig{Location: "Mars/Olympus"}, wantCode: codes.InvalidArgument},
+		{name: "bad credentials", err: rbc.ErrBadCredentials, wantCode: codes.Unauthenticated},
+		{name: "unexpected status", err: fmt.Errorf("%w: 503", rbc.ErrUnexpectedStatus), wantCode: codes.Unavailable},
+		{name: "other", err: errors.New("boom"), wantCode: codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := dial(t, NewServer(&fakeClient{err: tt.err}))
+			_, err := client.Parse(context.Background(), &rosbotpb.ParseRequest{Config: tt.config})
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("Parse() code = %s, want %s (%v)", got, tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestServer_Watch(t *testing.T) {
+	c := &fakeClient{updates: storetest.Updates()}
+	client := dial(t, NewServer(c, WithMinInterval(time.Millisecond)))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.Watch(ctx, &rosbotpb.WatchRequest{
+		Config:   &rosbotpb.ParserConfig{Quality: rosbotpb.Quality_QUALITY_SET, PageNumber: 4},
+		Interval: durationpb.New(time.Millisecond),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for len(ids) < 3 {
+		res, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, res.GetUpdate().GetId())
+	}
+	// Updates are streamed once, however many polls.
+	if want := []string{"u1", "u2", "u3"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Watch() = %v, want %v", ids, want)
+	}
+	cancel()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if got := c.configs[0]; got.Quality != rbc.QualitySet || got.PageNumber != rbc.FirstPage {
+		t.Errorf("config = %+v, want the first page of set items", got)
+	}
+}
+
+func TestServer_Watch_invalidConfig(t *testing.T) {
+	client := dial(t, NewServer(&fakeClient{}))
+	stream, err := client.Watch(context.Background(), &rosbotpb.WatchRequest{Config: &rosbotpb.ParserConfig{Quality: 42}})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Watch() error = %v, want InvalidArgument", err)
+	}
+}
+
+func Test_UpdateFromProto(t *testing.T) {
+	for _, u := range storetest.Updates() {
+		got := UpdateFromProto(UpdateToProto(u))
+		if !got.ServerTimestamp.Equal(u.ServerTimestamp) || !got.DerivedTimestamp.Equal(u.DerivedTimestamp) {
+			t.Errorf("%s: timestamps = %v, %v, want %v, %v", u.ID, got.ServerTimestamp, got.DerivedTimestamp, u.ServerTimestamp, u.DerivedTimestamp)
+		}
+		got.ServerTimestamp, got.DerivedTimestamp = u.ServerTimestamp, u.DerivedTimestamp
+		if !reflect.DeepEqual(got, u) {
+			t.Errorf("UpdateFromProto(UpdateToProto()) = %+v, want %+v", got, u)
+		}
+	}
+}
@@ -0,0 +1,395 @@
+// Package stats aggregates server updates into time-bucketed item counts, grouped by any
+// combination of rarity, quality, destination, item name and bot.
+//
+//	r, err := stats.Aggregate(updates, &stats.Config{
+//		Bucket:  time.Hour,
+//		GroupBy: []stats.Dimension{stats.Bot, stats.Destination},
+//	})
+//	ratio := r.Ratio(stats.Match{stats.Destination: "SALVAGED"}, stats.Match{stats.Destination: "STASHED"})
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	rbc "github.com/maxzaleski/go-rosbot-collector"
+)
+
+// Dimension is an item attribute by which counts are grouped.
+type Dimension string
+
+const (
+	Rarity      Dimension = "rarity"
+	Quality     Dimension = "quality"
+	Destination Dimension = "destination"
+	Name        Dimension = "name"
+	Bot         Dimension = "bot"
+)
+
+// MaxBuckets is the maximum number of buckets of a report.
+const MaxBuckets = 100000
+
+var (
+	// ErrInvalidDimension is returned when a dimension is unknown.
+	ErrInvalidDimension = errors.New("invalid dimension")
+	// ErrTooManyBuckets is returned when the period would be split into more than `MaxBuckets`.
+	ErrTooManyBuckets = errors.New("too many buckets")
+)
+
+// ParseDimensions parses a comma-separated list of dimensions, i.e. "bot,destination".
+func ParseDimensions(s string) ([]Dimension, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var dims []Dimension
+	for _, name := range strings.Split(s, ",") {
+		d := Dimension(strings.ToLower(strings.TrimSpace(name)))
+		if _, err := d.value(&rbc.LegendaryItem{}); err != nil {
+			return nil, err
+		}
+		dims = append(dims, d)
+	}
+	return dims, nil
+}
+
+func (d Dimension) value(item *rbc.LegendaryItem) (string, error) {
+	switch d {
+	case Rarity:
+		return string(item.Rarity), nil
+	case Quality:
+		return string(item.Quality), nil
+	case Destination:
+		return string(item.Destination), nil
+	case Name:
+		return item.Name, nil
+	case Bot:
+		return item.BotName, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidDimension, string(d))
+	}
+}
+
+type (
+	// Config configures an aggregation.
+	Config struct {
+		// Bucket is the duration of a bucket; zero means a single bucket spanning the period.
+		// Buckets of whole days start at midnight in `Location`.
+		Bucket time.Duration
+		// GroupBy are the dimensions items are grouped by; none means a single group.
+		GroupBy []Dimension
+		// Filter selects the counted items; nil means every item.
+		Filter rbc.Predicate
+		// From and To bound the period to [From, To). Zero bounds default to the oldest and
+		// newest server update timestamps.
+		From time.Time
+		To   time.Time
+		// Location aligns daily buckets; defaults to UTC.
+		Location *time.Location
+	}
+
+	// Report is the result of an aggregation.
+	Report struct {
+		From    time.Time
+		To      time.Time
+		GroupBy []Dimension
+		// Buckets are the starts of the buckets, oldest first; a bucket ends where the next starts,
+		// the last one at `To`.
+		Buckets []time.Time
+		// Totals are the item counts per bucket.
+		Totals []int
+		// Total is the number of items counted.
+		Total int
+		// Groups are ordered by decreasing count.
+		Groups []*Group
+		// Skipped is the number of items without a server timestamp, which are not counted.
+		Skipped int
+	}
+
+	// Group holds the counts of the items sharing the same dimension values.
+	Group struct {
+		// Key holds the values of `Report.GroupBy`, in order.
+		Key   []string
+		Count int
+		// Buckets are the counts per bucket of `Report.Buckets`.
+		Buckets []int
+		// Intervals are the delays between two consecutive server updates with items of the group.
+		Intervals *Intervals
+	}
+
+	// Match selects groups by some of their dimension values; an empty match selects every group.
+	Match map[Dimension]string
+)
+
+// Aggregate counts the items of the server updates.
+func Aggregate(updates []*rbc.ServerUpdate, config *Config) (*Report, error) {
+	c := Config{}
+	if config != nil {
+		c = *config
+	}
+	if c.Location == nil {
+		c.Location = time.UTC
+	}
+	for _, d := range c.GroupBy {
+		if _, err := d.value(&rbc.LegendaryItem{}); err != nil {
+			return nil, err
+		}
+	}
+
+	r := &Report{From: c.From, To: c.To, GroupBy: c.GroupBy}
+	within := rbc.CollectedBetween(c.From, c.To)
+	var counted []*rbc.ServerUpdate
+	for _, u := range updates {
+		if u.ServerTimestamp.IsZero() {
+			r.Skipped += len(u.Items)
+			continue
+		}
+		if !within(u, nil) {
+			continue
+		}
+		counted = append(counted, u)
+		if c.From.IsZero() && (r.From.IsZero() || u.ServerTimestamp.Before(r.From)) {
+			r.From = u.ServerTimestamp
+		}
+		// The period is exclusive of `To`; the newest update must be within it.
+		if c.To.IsZero() && !u.ServerTimestamp.Before(r.To) {
+			r.To = u.ServerTimestamp.Add(time.Nanosecond)
+		}
+	}
+	sort.SliceStable(counted, func(i, j int) bool {
+		return counted[i].ServerTimestamp.Before(counted[j].ServerTimestamp)
+	})
+
+	if err := r.split(c.Bucket, c.Location); err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string]*Group)
+	last := make(map[*Group]time.Time)
+	for _, u := range counted {
+		b := r.bucketOf(u.ServerTimestamp)
+		for _, item := range u.Items {
+			if c.Filter != nil && !c.Filter(u, item) {
+				continue
+			}
+			key := make([]string, len(c.GroupBy))
+			for i, d := range c.GroupBy {
+				key[i], _ = d.value(item)
+			}
+			id := strings.Join(key, "\x00")
+			g, ok := groups[id]
+			if !ok {
+				g = &Group{Key: key, Buckets: make([]int, len(r.Buckets)), Intervals: &Intervals{}}
+				groups[id] = g
+			}
+
+			g.Count++
+			g.Buckets[b]++
+			r.Totals[b]++
+			r.Total++
+
+			// Items of the same update share its timestamp; they are a single drop.
+			if prev, ok := last[g]; !ok || !prev.Equal(u.ServerTimestamp) {
+				if ok {
+					g.Intervals.add(u.ServerTimestamp.Sub(prev))
+				}
+				last[g] = u.ServerTimestamp
+			}
+		}
+	}
+
+	for _, g := range groups {
+		g.Intervals.sort()
+		r.Groups = append(r.Groups, g)
+	}
+	sort.Slice(r.Groups, func(i, j int) bool {
+		a, b := r.Groups[i], r.Groups[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return strings.Join(a.Key, "\x00") < strings.Join(b.Key, "\x00")
+	})
+	return r, nil
+}
+
+// split divides the period into buckets.
+func (r *Report) split(bucket time.Duration, loc *time.Location) error {
+	if r.From.IsZero() {
+		return nil
+	}
+	if bucket <= 0 {
+		r.Buckets, r.Totals = []time.Time{r.From}, []int{0}
+		return nil
+	}
+
+	days := 0
+	if bucket%(24*time.Hour) == 0 {
+		days = int(bucket / (24 * time.Hour))
+	}
+	start := r.From.Truncate(bucket)
+	if days > 0 {
+		y, m, d := r.From.In(loc).Date()
+		start = time.Date(y, m, d, 0, 0, 0, 0, loc)
+	}
+
+	for t := start; t.Before(r.To); {
+		if len(r.Buckets) == MaxBuckets {
+			return fmt.Errorf("%w: more than %d buckets of %s", ErrTooManyBuckets, MaxBuckets, bucket)
+		}
+		r.Buckets = append(r.Buckets, t)
+		// Days are added on the calendar, so that buckets remain aligned across DST changes.
+		if days > 0 {
+			t = t.AddDate(0, 0, days)
+		} else {
+			t = t.Add(bucket)
+		}
+	}
+	r.Totals = make([]int, len(r.Buckets))
+	return nil
+}
+
+func (r *Report) bucketOf(t time.Time) int {
+	return sort.Search(len(r.Buckets), func(i int) bool { return r.Buckets[i].After(t) }) - 1
+}
+
+// Duration is the length of the period.
+func (r *Report) Duration() time.Duration {
+	return r.To.Sub(r.From)
+}
+
+// Group returns the group of the given key, or nil.
+func (r *Report) Group(key ...string) *Group {
+	for _, g := range r.Groups {
+		if strings.Join(g.Key, "\x00") == strings.Join(key, "\x00") {
+			return g
+		}
+	}
+	return nil
+}
+
+// Count returns the number of items of the matching groups. Dimensions which are not grouped by
+// cannot be matched; they never match.
+func (r *Report) Count(m Match) int {
+	n := 0
+	for _, g := range r.Groups {
+		if r.matches(g, m) {
+			n += g.Count
+		}
+	}
+	return n
+}
+
+// Share returns the fraction of the items which belong to the matching groups, i.e. the primal
+// rate with `Match{Rarity: "PRIMAL"}`.
+func (r *Report) Share(m Match) float64 {
+	if r.Total == 0 {
+		return 0
+	}
+	return float64(r.Count(m)) / float64(r.Total)
+}
+
+// Resolution is the resolution of server timestamps; no rate is computed over a shorter period.
+const Resolution = time.Minute
+
+// Rate returns the number of items of the matching groups per `per`, i.e. ancients per hour with
+// `Rate(Match{Rarity: "ANCIENT"}, time.Hour)`. It is zero when the period is shorter than
+// `Resolution`, i.e. when every update is of the same minute.
+func (r *Report) Rate(m Match, per time.Duration) float64 {
+	d := r.Duration()
+	if d < Resolution {
+		return 0
+	}
+	return float64(r.Count(m)) * float64(per) / float64(d)
+}
+
+// Ratio returns the number of items matching `num` divided by those matching `den`, i.e. the
+// salvage to stash ratio of a bot. It is zero when no item matches `den`.
+func (r *Report) Ratio(num, den Match) float64 {
+	d := r.Count(den)
+	if d == 0 {
+		return 0
+	}
+	return float64(r.Count(num)) / float64(d)
+}
+
+func (r *Report) matches(g *Group, m Match) bool {
+	for d, v := range m {
+		found := false
+		for i, gd := range r.GroupBy {
+			if gd == d {
+				found = strings.EqualFold(g.Key[i], v)
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Label returns the key of the group joined by "/", i.e. "ANCIENT/STASHED"; "*" for a single group.
+func (g *Group) Label() string {
+	if len(g.Key) == 0 {
+		return "*"
+	}
+	return strings.Join(g.Key, "/")
+}
+
+// Intervals are the delays between consecutive drops.
+type Intervals struct {
+	sorted []time.Duration
+	sum    time.Duration
+}
+
+func (in *Intervals) add(d time.Duration) {
+	in.sorted = append(in.sorted, d)
+	in.sum += d
+}
+
+func (in *Intervals) sort() {
+	sort.Slice(in.sorted, func(i, j int) bool { return in.sorted[i] < in.sorted[j] })
+}
+
+// Count is the number of intervals; one less than the number of drops.
+func (in *Intervals) Count() int {
+	return len(in.sorted)
+}
+
+// Mean returns the average interval, or zero without interval.
+func (in *Intervals) Mean() time.Duration {
+	if len(in.sorted) == 0 {
+		return 0
+	}
+	return in.sum / time.Duration(len(in.sorted))
+}
+
+// Min returns the shortest interval, or zero without interval.
+func (in *Intervals) Min() time.Duration {
+	return in.Percentile(0)
+}
+
+// Max returns the longest interval, or zero without interval.
+func (in *Intervals) Max() time.Duration {
+	return in.Percentile(100)
+}
+
+// Percentile returns the p-th percentile (0 to 100) of the intervals, by nearest rank; zero
+// without interval.
+func (in *Intervals) Percentile(p float64) time.Duration {
+	n := len(in.sorted)
+	if n == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(n)))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > n {
+		rank = n
+	}
+	return in.sorted[rank-1]
+}
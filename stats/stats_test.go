@@ -0,0 +1,230 @@
+package stats
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	rbc "github.com/maxzaleski/go-rosbot-collector"
+)
+
+var t0 = time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func item(name string, r rbc.Rarity, d rbc.Destination, bot string) *rbc.LegendaryItem {
+	return &rbc.LegendaryItem{Name: name, Quality: rbc.QualityNormal, Rarity: r, Destination: d, BotName: bot}
+}
+
+// testUpdates span two and a half hours; one update has no timestamp.
+func testUpdates() []*rbc.ServerUpdate {
+	return []*rbc.ServerUpdate{
+		{ServerTimestamp: t0.Add(30 * time.Minute), Items: []*rbc.LegendaryItem{
+			item("Furnace", rbc.RarityAncient, rbc.DestinationStashed, "Barb"),
+			item("Unidentified", rbc.RarityNonAncient, rbc.DestinationSalvaged, "Barb"),
+		}},
+		{ServerTimestamp: t0, Items: []*rbc.LegendaryItem{
+			item("Stone of Jordan", rbc.RarityPrimal, rbc.DestinationStashed, "Wiz"),
+			item("Furnace", rbc.RarityAncient, rbc.DestinationSalvaged, "Barb"),
+		}},
+		{ServerTimestamp: t0.Add(150 * time.Minute), Items: []*rbc.LegendaryItem{
+			item("Unidentified", rbc.RarityNonAncient, rbc.DestinationSalvaged, "Barb"),
+			item("Tyrael's Might", rbc.RarityAncient, rbc.DestinationStashed, "Barb"),
+		}},
+		{Items: []*rbc.LegendaryItem{item("Furnace", rbc.RarityAncient, rbc.DestinationSold, "Wiz")}},
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	r, err := Aggregate(testUpdates(), &Config{Bucket: time.Hour, GroupBy: []Dimension{Rarity}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Total != 6 || r.Skipped != 1 {
+		t.Errorf("Total, Skipped = %d, %d, want 6, 1", r.Total, r.Skipped)
+	}
+	if !r.From.Equal(t0) || !r.To.Equal(t0.Add(150*time.Minute+time.Nanosecond)) {
+		t.Errorf("From, To = %v, %v", r.From, r.To)
+	}
+	wantBuckets := []time.Time{t0, t0.Add(time.Hour), t0.Add(2 * time.Hour)}
+	if !reflect.DeepEqual(r.Buckets, wantBuckets) {
+		t.Errorf("Buckets = %v, want %v", r.Buckets, wantBuckets)
+	}
+	if want := []int{4, 0, 2}; !reflect.DeepEqual(r.Totals, want) {
+		t.Errorf("Totals = %v, want %v", r.Totals, want)
+	}
+
+	var labels []string
+	for _, g := range r.Groups {
+		labels = append(labels, g.Label())
+	}
+	if want := []string{"ANCIENT", "NON-ANCIENT", "PRIMAL"}; !reflect.DeepEqual(labels, want) {
+		t.Errorf("Groups = %v, want %v", labels, want)
+	}
+	ancient := r.Group("ANCIENT")
+	if ancient.Count != 3 || !reflect.DeepEqual(ancient.Buckets, []int{2, 0, 1}) {
+		t.Errorf("ANCIENT group = %d %v, want 3 [2 0 1]", ancient.Count, ancient.Buckets)
+	}
+}
+
+func TestAggregate_config(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *Config
+		wantTotal   int
+		wantBuckets int
+		wantErr     error
+	}{
+		{name: "nil", config: nil, wantTotal: 6, wantBuckets: 1},
+		{name: "bounds", config: &Config{From: t0.Add(time.Minute), To: t0.Add(time.Hour)}, wantTotal: 2, wantBuckets: 1},
+		{name: "filter", config: &Config{Filter: rbc.BotIs("wiz")}, wantTotal: 1, wantBuckets: 1},
+		{name: "invalid dimension", config: &Config{GroupBy: []Dimension{"colour"}}, wantErr: ErrInvalidDimension},
+		{name: "too many buckets", config: &Config{Bucket: time.Millisecond}, wantErr: ErrTooManyBuckets},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Aggregate(testUpdates(), tt.config)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Aggregate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if r.Total != tt.wantTotal || len(r.Buckets) != tt.wantBuckets {
+				t.Errorf("Aggregate() = %d items in %d buckets, want %d in %d", r.Total, len(r.Buckets), tt.wantTotal, tt.wantBuckets)
+			}
+		})
+	}
+}
+
+func TestAggregate_dailyBuckets(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip(err)
+	}
+	// Paris switches to summer time on 2020-03-29.
+	updates := []*rbc.ServerUpdate{
+		{ServerTimestamp: time.Date(2020, 3, 28, 23, 30, 0, 0, paris), Items: []*rbc.LegendaryItem{{}}},
+		{ServerTimestamp: time.Date(2020, 3, 30, 0, 30, 0, 0, paris), Items: []*rbc.LegendaryItem{{}}},
+	}
+	r, err := Aggregate(updates, &Config{Bucket: 24 * time.Hour, Location: paris})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []time.Time{
+		time.Date(2020, 3, 28, 0, 0, 0, 0, paris),
+		time.Date(2020, 3, 29, 0, 0, 0, 0, paris),
+		time.Date(2020, 3, 30, 0, 0, 0, 0, paris),
+	}
+	if len(r.Buckets) != len(want) {
+		t.Fatalf("Buckets = %v, want %v", r.Buckets, want)
+	}
+	for i := range want {
+		if !r.Buckets[i].Equal(want[i]) {
+			t.Errorf("Buckets[%d] = %v, want %v", i, r.Buckets[i], want[i])
+		}
+	}
+	if !reflect.DeepEqual(r.Totals, []int{1, 0, 1}) {
+		t.Errorf("Totals = %v, want [1 0 1]", r.Totals)
+	}
+}
+
+func TestReport_rates(t *testing.T) {
+	r, err := Aggregate(testUpdates(), &Config{
+		GroupBy: []Dimension{Bot, Destination, Rarity},
+		From:    t0,
+		To:      t0.Add(3 * time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{name: "count", got: float64(r.Count(Match{Bot: "barb"})), want: 5},
+		{name: "ungrouped dimension", got: float64(r.Count(Match{Name: "Furnace"})), want: 0},
+		{name: "share", got: r.Share(Match{Rarity: "PRIMAL"}), want: 1.0 / 6},
+		{name: "rate", got: r.Rate(Match{Rarity: "ANCIENT"}, time.Hour), want: 1},
+		{name: "ratio", got: r.Ratio(Match{Bot: "Barb", Destination: "SALVAGED"}, Match{Bot: "Barb", Destination: "STASHED"}), want: 1.5},
+		{name: "ratio without denominator", got: r.Ratio(Match{}, Match{Bot: "Monk"}), want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if math.Abs(tt.got-tt.want) > 1e-9 {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReport_Rate_belowResolution(t *testing.T) {
+	// Every update is of the same minute; the period is a nanosecond long.
+	updates := testUpdates()[1:2]
+	r, err := Aggregate(updates, &Config{GroupBy: []Dimension{Rarity}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Rate(Match{}, time.Hour); got != 0 {
+		t.Errorf("Rate() = %v, want 0", got)
+	}
+}
+
+func TestIntervals(t *testing.T) {
+	r, err := Aggregate(testUpdates(), &Config{GroupBy: []Dimension{Bot}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Barb's drops: 12:00, 12:30 (two items, one drop) and 14:30.
+	in := r.Group("Barb").Intervals
+	if in.Count() != 2 {
+		t.Fatalf("Count() = %d, want 2", in.Count())
+	}
+	tests := []struct {
+		name string
+		got  time.Duration
+		want time.Duration
+	}{
+		{name: "min", got: in.Min(), want: 30 * time.Minute},
+		{name: "max", got: in.Max(), want: 2 * time.Hour},
+		{name: "mean", got: in.Mean(), want: 75 * time.Minute},
+		{name: "p50", got: in.Percentile(50), want: 30 * time.Minute},
+		{name: "p90", got: in.Percentile(90), want: 2 * time.Hour},
+		{name: "single drop", got: r.Group("Wiz").Intervals.Percentile(50), want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ParseDimensions(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    []Dimension
+		wantErr bool
+	}{
+		{s: "", want: nil},
+		{s: "bot, Destination", want: []Dimension{Bot, Destination}},
+		{s: "rarity,quality,name", want: []Dimension{Rarity, Quality, Name}},
+		{s: "bot,colour", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := ParseDimensions(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDimensions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDimensions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
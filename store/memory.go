@@ -0,0 +1,161 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	rbc "github.com/maxzaleski/go-rosbot-collector"
+)
+
+// Memory is a `Store` held in memory; its content is lost on exit.
+type Memory struct {
+	mu      sync.RWMutex
+	updates map[string]*rbc.ServerUpdate
+	items   map[string]*rbc.ItemRecord
+}
+
+// NewMemory returns a new instance of `store.Memory`.
+func NewMemory() *Memory {
+	return &Memory{
+		updates: make(map[string]*rbc.ServerUpdate),
+		items:   make(map[string]*rbc.ItemRecord),
+	}
+}
+
+// SaveUpdates implements `Store`.
+func (m *Memory) SaveUpdates(_ context.Context, updates []*rbc.ServerUpdate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, u := range updates {
+		if old, ok := m.updates[u.ID]; ok {
+			for _, item := range old.Items {
+				delete(m.items, item.ID)
+			}
+		}
+		u = copyUpdate(u, u.Items)
+		m.updates[u.ID] = u
+		for _, item := range u.Items {
+			// An item saved under another update moves to this one.
+			if old, ok := m.items[item.ID]; ok && old.UpdateID != u.ID {
+				m.removeItem(old.UpdateID, item.ID)
+			}
+			m.items[item.ID] = &rbc.ItemRecord{UpdateID: u.ID, ServerTimestamp: u.ServerTimestamp, LegendaryItem: item}
+		}
+	}
+	return nil
+}
+
+// removeItem removes the item from the items of the saved update.
+func (m *Memory) removeItem(updateID, itemID string) {
+	u, ok := m.updates[updateID]
+	if !ok {
+		return
+	}
+	items := make([]*rbc.LegendaryItem, 0, len(u.Items))
+	for _, item := range u.Items {
+		if item.ID != itemID {
+			items = append(items, item)
+		}
+	}
+	u.Items = items
+}
+
+// Update implements `Store`.
+func (m *Memory) Update(_ context.Context, id string) (*rbc.ServerUpdate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	u, ok := m.updates[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return copyUpdate(u, u.Items), nil
+}
+
+// Item implements `Store`.
+func (m *Memory) Item(_ context.Context, id string) (*rbc.ItemRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	r, ok := m.items[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	item := *r.LegendaryItem
+	return &rbc.ItemRecord{UpdateID: r.UpdateID, ServerTimestamp: r.ServerTimestamp, LegendaryItem: &item}, nil
+}
+
+// Updates implements `Store`.
+func (m *Memory) Updates(_ context.Context, q *Query) ([]*rbc.ServerUpdate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	match := q.Predicate()
+	var updates []*rbc.ServerUpdate
+	for _, u := range m.sorted() {
+		var items []*rbc.LegendaryItem
+		for _, item := range u.Items {
+			if match(u, item) {
+				items = append(items, item)
+			}
+		}
+		if len(items) > 0 {
+			updates = append(updates, copyUpdate(u, items))
+		}
+	}
+	start, end := q.page(len(updates))
+	return updates[start:end], nil
+}
+
+// Items implements `Store`.
+func (m *Memory) Items(ctx context.Context, q *Query) ([]*rbc.ItemRecord, error) {
+	// Limit and offset apply to items, not updates.
+	var all *Query
+	if q != nil {
+		unpaged := *q
+		unpaged.Limit, unpaged.Offset = 0, 0
+		all = &unpaged
+	}
+	updates, err := m.Updates(ctx, all)
+	if err != nil {
+		return nil, err
+	}
+	records := rbc.FlattenUpdates(updates)
+	start, end := q.page(len(records))
+	return records[start:end], nil
+}
+
+// Close implements `Store`.
+func (m *Memory) Close() error {
+	return nil
+}
+
+// sorted returns the updates oldest first; it must be called with the lock held.
+func (m *Memory) sorted() []*rbc.ServerUpdate {
+	updates := make([]*rbc.ServerUpdate, 0, len(m.updates))
+	for _, u := range m.updates {
+		updates = append(updates, u)
+	}
+	sort.Slice(updates, func(i, j int) bool {
+		a, b := updates[i], updates[j]
+		if !a.ServerTimestamp.Equal(b.ServerTimestamp) {
+			return a.ServerTimestamp.Before(b.ServerTimestamp)
+		}
+		return a.ID < b.ID
+	})
+	return updates
+}
+
+// copyUpdate copies the update with the given items, so that callers cannot alter the stored ones.
+func copyUpdate(u *rbc.ServerUpdate, items []*rbc.LegendaryItem) *rbc.ServerUpdate {
+	c := *u
+	c.Items = make([]*rbc.LegendaryItem, len(items))
+	for i, item := range items {
+		copied := *item
+		c.Items[i] = &copied
+	}
+	c.Warnings = append([]*rbc.ParseWarning(nil), u.Warnings...)
+	return &c
+}
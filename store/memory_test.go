@@ -0,0 +1,12 @@
+package store_test
+
+import (
+	"testing"
+
+	"github.com/maxzaleski/go-rosbot-collector/store"
+	"github.com/maxzaleski/go-rosbot-collector/store/storetest"
+)
+
+func TestMemory(t *testing.T) {
+	storetest.Run(t, func(*testing.T) store.Store { return store.NewMemory() })
+}
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// migrations are applied in order, once; the schema version is the number of applied migrations.
+// Applied migrations must never be edited, only appended to.
+var migrations = []string{
+	`CREATE TABLE updates (
+		id text PRIMARY KEY,
+		server_timestamp timestamptz,
+		derived_timestamp timestamptz,
+		utc_offset bigint NOT NULL DEFAULT 0,
+		raw_timestamp text NOT NULL DEFAULT '',
+		raw_relative_time text NOT NULL DEFAULT '',
+		warnings jsonb NOT NULL DEFAULT '[]'
+	);
+	CREATE INDEX updates_server_timestamp ON updates (server_timestamp);
+
+	CREATE TABLE items (
+		id text PRIMARY KEY,
+		update_id text NOT NULL REFERENCES updates (id) ON DELETE CASCADE,
+		idx integer NOT NULL,
+		name text NOT NULL,
+		quality text NOT NULL,
+		rarity text NOT NULL,
+		destination text NOT NULL,
+		identified boolean NOT NULL,
+		bot_name text NOT NULL
+	);
+	CREATE INDEX items_update_id ON items (update_id, idx);
+	CREATE INDEX items_rarity ON items (rarity);
+	CREATE INDEX items_name ON items (lower(name));
+
+	CREATE TABLE affixes (
+		item_id text NOT NULL REFERENCES items (id) ON DELETE CASCADE,
+		position integer NOT NULL,
+		text text NOT NULL,
+		PRIMARY KEY (item_id, position)
+	);`,
+}
+
+// migrationLock is the key of the advisory lock serialising concurrent migrations,
+// i.e. of several collectors started at once.
+const migrationLock = 7265627374 // "rbst"
+
+// Version returns the schema version of the database.
+func (s *Store) Version(ctx context.Context) (int, error) {
+	var v int
+	err := s.pool.QueryRow(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&v)
+	return v, err
+}
+
+// migrate applies the pending migrations within a single transaction; Postgres DDL is
+// transactional, so a failed migration leaves the schema untouched.
+func (s *Store) migrate(ctx context.Context) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationLock); err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version integer PRIMARY KEY,
+		applied_at timestamptz NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return err
+	}
+
+	var current int
+	if err := tx.QueryRow(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return err
+	}
+	if current > len(migrations) {
+		return fmt.Errorf("database schema version %d is newer than supported version %d", current, len(migrations))
+	}
+
+	for v := current + 1; v <= len(migrations); v++ {
+		if err := apply(ctx, tx, v, migrations[v-1]); err != nil {
+			return fmt.Errorf("migration %d: %w", v, err)
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func apply(ctx context.Context, tx pgx.Tx, version int, migration string) error {
+	if _, err := tx.Exec(ctx, migration); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, version)
+	return err
+}
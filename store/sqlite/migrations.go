@@ -0,0 +1,84 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+)
+
+// migrations are applied in order, once; the schema version is the number of applied migrations.
+// Applied migrations must never be edited, only appended to.
+var migrations = []string{
+	`CREATE TABLE updates (
+		id TEXT PRIMARY KEY,
+		server_timestamp INTEGER,
+		derived_timestamp INTEGER,
+		utc_offset INTEGER NOT NULL DEFAULT 0,
+		raw_timestamp TEXT NOT NULL DEFAULT '',
+		raw_relative_time TEXT NOT NULL DEFAULT '',
+		warnings TEXT NOT NULL DEFAULT 'null'
+	);
+	CREATE INDEX updates_server_timestamp ON updates (server_timestamp);
+	CREATE TABLE items (
+		id TEXT PRIMARY KEY,
+		update_id TEXT NOT NULL REFERENCES updates (id) ON DELETE CASCADE,
+		idx INTEGER NOT NULL,
+		name TEXT NOT NULL,
+		quality TEXT NOT NULL,
+		rarity TEXT NOT NULL,
+		destination TEXT NOT NULL,
+		identified INTEGER NOT NULL,
+		stats TEXT NOT NULL,
+		bot_name TEXT NOT NULL
+	);
+	CREATE INDEX items_update_id ON items (update_id, idx);
+	CREATE INDEX items_rarity ON items (rarity);
+	CREATE INDEX items_name ON items (name COLLATE NOCASE);`,
+}
+
+// Version returns the schema version of the database.
+func (s *Store) Version(ctx context.Context) (int, error) {
+	var v int
+	err := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&v)
+	return v, err
+}
+
+func (s *Store) migrate(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		applied_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		return err
+	}
+
+	current, err := s.Version(ctx)
+	if err != nil {
+		return err
+	}
+	if current > len(migrations) {
+		return fmt.Errorf("database schema version %d is newer than supported version %d", current, len(migrations))
+	}
+
+	for v := current + 1; v <= len(migrations); v++ {
+		if err := s.apply(ctx, v, migrations[v-1]); err != nil {
+			return fmt.Errorf("migration %d: %w", v, err)
+		}
+	}
+	return nil
+}
+
+func (s *Store) apply(ctx context.Context, version int, migration string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, migration); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES (?)`, version); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
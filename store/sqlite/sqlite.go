@@ -0,0 +1,371 @@
+// Package sqlite is a `store.Store` backed by an embedded SQLite database, through a pure-Go
+// driver; no cgo nor database server is required.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	rbc "github.com/maxzaleski/go-rosbot-collector"
+	"github.com/maxzaleski/go-rosbot-collector/store"
+
+	// Registers the "sqlite" driver.
+	_ "modernc.org/sqlite"
+)
+
+// Store is a `store.Store` backed by SQLite.
+type Store struct {
+	db *sql.DB
+}
+
+var _ store.Store = (*Store)(nil)
+
+// Open opens, or creates, the database at `path`, and applies the pending migrations.
+// ":memory:" opens a private in-memory database.
+func Open(ctx context.Context, path string) (*Store, error) {
+	params := url.Values{"_pragma": {"foreign_keys(1)", "busy_timeout(5000)"}}
+	if path != ":memory:" {
+		params.Add("_pragma", "journal_mode(WAL)")
+	}
+	db, err := sql.Open("sqlite", "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+	if path == ":memory:" {
+		// Every connection would otherwise open its own database.
+		db.SetMaxOpenConns(1)
+	}
+
+	s := &Store{db: db}
+	if err := s.migrate(ctx); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// DB returns the underlying database, i.e. for ad-hoc queries.
+func (s *Store) DB() *sql.DB {
+	return s.db
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// SaveUpdates implements `store.Store`. The updates are saved within a single transaction.
+func (s *Store) SaveUpdates(ctx context.Context, updates []*rbc.ServerUpdate) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	upsertUpdate, err := tx.PrepareContext(ctx, `
+		INSERT INTO updates (id, server_timestamp, derived_timestamp, utc_offset, raw_timestamp, raw_relative_time, warnings)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			server_timestamp = excluded.server_timestamp,
+			derived_timestamp = excluded.derived_timestamp,
+			utc_offset = excluded.utc_offset,
+			raw_timestamp = excluded.raw_timestamp,
+			raw_relative_time = excluded.raw_relative_time,
+			warnings = excluded.warnings`)
+	if err != nil {
+		return err
+	}
+	defer upsertUpdate.Close()
+	deleteItems, err := tx.PrepareContext(ctx, `DELETE FROM items WHERE update_id = ?`)
+	if err != nil {
+		return err
+	}
+	defer deleteItems.Close()
+	insertItem, err := tx.PrepareContext(ctx, `
+		INSERT INTO items (id, update_id, idx, name, quality, rarity, destination, identified, stats, bot_name)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			update_id = excluded.update_id,
+			idx = excluded.idx,
+			name = excluded.name,
+			quality = excluded.quality,
+			rarity = excluded.rarity,
+			destination = excluded.destination,
+			identified = excluded.identified,
+			stats = excluded.stats,
+			bot_name = excluded.bot_name`)
+	if err != nil {
+		return err
+	}
+	defer insertItem.Close()
+
+	for _, u := range updates {
+		warnings, err := json.Marshal(u.Warnings)
+		if err != nil {
+			return err
+		}
+		_, err = upsertUpdate.ExecContext(ctx, u.ID, timeValue(u.ServerTimestamp), timeValue(u.DerivedTimestamp),
+			int64(u.UTCOffset), u.RawTimestamp, u.RawRelativeTime, string(warnings))
+		if err != nil {
+			return fmt.Errorf("saving update %s: %w", u.ID, err)
+		}
+
+		if _, err := deleteItems.ExecContext(ctx, u.ID); err != nil {
+			return err
+		}
+		for _, item := range u.Items {
+			_, err := insertItem.ExecContext(ctx, item.ID, u.ID, item.Index, item.Name, string(item.Quality),
+				string(item.Rarity), string(item.Destination), item.IsIdentified, item.Stats, item.BotName)
+			if err != nil {
+				return fmt.Errorf("saving item %s: %w", item.ID, err)
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// Update implements `store.Store`.
+func (s *Store) Update(ctx context.Context, id string) (*rbc.ServerUpdate, error) {
+	u, err := scanUpdate(s.db.QueryRowContext(ctx, `SELECT `+updateColumns+` FROM updates u WHERE u.id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, store.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := s.items(ctx, `i.update_id = ?`, []interface{}{id}, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range records {
+		u.Items = append(u.Items, r.LegendaryItem)
+	}
+	return u, nil
+}
+
+// Item implements `store.Store`.
+func (s *Store) Item(ctx context.Context, id string) (*rbc.ItemRecord, error) {
+	records, err := s.items(ctx, `i.id = ?`, []interface{}{id}, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, store.ErrNotFound
+	}
+	return records[0], nil
+}
+
+// Items implements `store.Store`.
+func (s *Store) Items(ctx context.Context, q *store.Query) ([]*rbc.ItemRecord, error) {
+	where, args := whereClause(q)
+	return s.items(ctx, where, args, limitClause(q))
+}
+
+// Updates implements `store.Store`.
+func (s *Store) Updates(ctx context.Context, q *store.Query) ([]*rbc.ServerUpdate, error) {
+	where, args := whereClause(q)
+	// The page applies to updates; their matching items are selected afterwards.
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+updateColumns+` FROM updates u
+		WHERE u.id IN (SELECT i.update_id FROM items i JOIN updates u ON u.id = i.update_id WHERE `+where+`)
+		ORDER BY u.server_timestamp, u.id`+limitClause(q), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		updates []*rbc.ServerUpdate
+		ids     []interface{}
+		byID    = make(map[string]*rbc.ServerUpdate)
+	)
+	for rows.Next() {
+		u, err := scanUpdate(rows)
+		if err != nil {
+			return nil, err
+		}
+		updates = append(updates, u)
+		ids = append(ids, u.ID)
+		byID[u.ID] = u
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(updates) == 0 {
+		return nil, nil
+	}
+
+	in := `i.update_id IN (?` + strings.Repeat(", ?", len(ids)-1) + `)`
+	records, err := s.items(ctx, in+` AND `+where, append(ids, args...), "")
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range records {
+		u := byID[r.UpdateID]
+		u.Items = append(u.Items, r.LegendaryItem)
+	}
+	return updates, nil
+}
+
+const updateColumns = `u.id, u.server_timestamp, u.derived_timestamp, u.utc_offset, u.raw_timestamp, u.raw_relative_time, u.warnings`
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanUpdate(row scanner) (*rbc.ServerUpdate, error) {
+	var (
+		u               rbc.ServerUpdate
+		server, derived sql.NullInt64
+		offset          int64
+		warnings        string
+	)
+	err := row.Scan(&u.ID, &server, &derived, &offset, &u.RawTimestamp, &u.RawRelativeTime, &warnings)
+	if err != nil {
+		return nil, err
+	}
+	u.ServerTimestamp, u.DerivedTimestamp = timeFrom(server), timeFrom(derived)
+	u.UTCOffset = time.Duration(offset)
+	if err := json.Unmarshal([]byte(warnings), &u.Warnings); err != nil {
+		return nil, fmt.Errorf("update %s warnings: %w", u.ID, err)
+	}
+	return &u, nil
+}
+
+func (s *Store) items(ctx context.Context, where string, args []interface{}, limit string) ([]*rbc.ItemRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT i.id, i.update_id, u.server_timestamp, i.idx, i.name, i.quality, i.rarity, i.destination, i.identified, i.stats, i.bot_name
+		FROM items i JOIN updates u ON u.id = i.update_id
+		WHERE `+where+`
+		ORDER BY u.server_timestamp, u.id, i.idx`+limit, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []*rbc.ItemRecord
+	for rows.Next() {
+		var (
+			item   rbc.LegendaryItem
+			r      = rbc.ItemRecord{LegendaryItem: &item}
+			ts     sql.NullInt64
+			q, rar string
+			dest   string
+		)
+		err := rows.Scan(&item.ID, &r.UpdateID, &ts, &item.Index, &item.Name, &q, &rar, &dest,
+			&item.IsIdentified, &item.Stats, &item.BotName)
+		if err != nil {
+			return nil, err
+		}
+		r.ServerTimestamp = timeFrom(ts)
+		item.Quality, item.Rarity, item.Destination = rbc.Quality(q), rbc.Rarity(rar), rbc.Destination(dest)
+		records = append(records, &r)
+	}
+	return records, rows.Err()
+}
+
+// whereClause translates the query into conditions on items `i` and updates `u`.
+func whereClause(q *store.Query) (string, []interface{}) {
+	conditions := []string{"1 = 1"}
+	var args []interface{}
+	if q == nil {
+		return conditions[0], nil
+	}
+
+	if !q.From.IsZero() {
+		conditions = append(conditions, "u.server_timestamp >= ?")
+		args = append(args, q.From.UnixNano())
+	}
+	if !q.To.IsZero() {
+		conditions = append(conditions, "u.server_timestamp < ?")
+		args = append(args, q.To.UnixNano())
+	}
+	in := func(column string, values []string) {
+		if len(values) == 0 {
+			conditions = append(conditions, "0 = 1")
+			return
+		}
+		conditions = append(conditions, column+" IN (?"+strings.Repeat(", ?", len(values)-1)+")")
+		for _, v := range values {
+			args = append(args, v)
+		}
+	}
+	if rarities := q.RarityIn(); rarities != nil {
+		values := make([]string, len(rarities))
+		for i, r := range rarities {
+			values[i] = string(r)
+		}
+		in("i.rarity", values)
+	}
+	if len(q.Qualities) > 0 && !containsAll(q.Qualities) {
+		values := make([]string, len(q.Qualities))
+		for i, v := range q.Qualities {
+			values[i] = string(v)
+		}
+		in("i.quality", values)
+	}
+	if len(q.Destinations) > 0 {
+		values := make([]string, len(q.Destinations))
+		for i, v := range q.Destinations {
+			values[i] = string(v)
+		}
+		in("i.destination", values)
+	}
+	if q.Name != "" {
+		conditions = append(conditions, `i.name LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+escapeLike(q.Name)+"%")
+	}
+	if q.Bot != "" {
+		conditions = append(conditions, "i.bot_name = ? COLLATE NOCASE")
+		args = append(args, q.Bot)
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+func limitClause(q *store.Query) string {
+	if q == nil || (q.Limit <= 0 && q.Offset <= 0) {
+		return ""
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+	return fmt.Sprintf(" LIMIT %d OFFSET %d", limit, q.Offset)
+}
+
+func containsAll(qualities []rbc.Quality) bool {
+	for _, q := range qualities {
+		if q == rbc.QualityAll {
+			return true
+		}
+	}
+	return false
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// timeValue stores timestamps as Unix nanoseconds; zero timestamps as NULL.
+func timeValue(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UnixNano()
+}
+
+func timeFrom(v sql.NullInt64) time.Time {
+	if !v.Valid {
+		return time.Time{}
+	}
+	return time.Unix(0, v.Int64).UTC()
+}
@@ -0,0 +1,72 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/maxzaleski/go-rosbot-collector/store"
+	"github.com/maxzaleski/go-rosbot-collector/store/storetest"
+)
+
+func TestStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.Store {
+		s, err := Open(context.Background(), ":memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	})
+}
+
+func TestOpen_persistence(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "drops.db")
+
+	s, err := Open(ctx, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveUpdates(ctx, storetest.Updates()); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reopening must not re-apply the migrations.
+	s, err = Open(ctx, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if v, err := s.Version(ctx); err != nil || v != len(migrations) {
+		t.Errorf("Version() = %d, %v, want %d", v, err, len(migrations))
+	}
+	items, err := s.Items(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 4 {
+		t.Errorf("Items() returned %d items, want 4", len(items))
+	}
+}
+
+func TestOpen_newerSchema(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "drops.db")
+
+	s, err := Open(ctx, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.DB().ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES (?)`, len(migrations)+1); err != nil {
+		t.Fatal(err)
+	}
+	_ = s.Close()
+
+	if _, err := Open(ctx, path); err == nil {
+		t.Error("Open() accepted a newer schema version")
+	}
+}
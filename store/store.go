@@ -0,0 +1,124 @@
+// Package store persists collected server updates, and queries their items.
+//
+// `Memory` is an in-process implementation; persistent ones live in the sub-packages, i.e.
+// `store/sqlite`.
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	rbc "github.com/maxzaleski/go-rosbot-collector"
+)
+
+// ErrNotFound is returned when no server update, or item, has the requested ID.
+var ErrNotFound = errors.New("not found")
+
+// Store persists server updates and their items, keyed by their IDs. Since update IDs depend on
+// the parsing configuration, a store is fed with updates parsed through a single one.
+type Store interface {
+	// SaveUpdates upserts the server updates; the items of an already saved update are replaced.
+	SaveUpdates(ctx context.Context, updates []*rbc.ServerUpdate) error
+	// Update returns the server update with the given ID, or `ErrNotFound`.
+	Update(ctx context.Context, id string) (*rbc.ServerUpdate, error)
+	// Item returns the item with the given ID, or `ErrNotFound`.
+	Item(ctx context.Context, id string) (*rbc.ItemRecord, error)
+	// Updates returns the server updates with at least one item matching the query, oldest first.
+	// Only the matching items are returned.
+	Updates(ctx context.Context, q *Query) ([]*rbc.ServerUpdate, error)
+	// Items returns the items matching the query, oldest first.
+	Items(ctx context.Context, q *Query) ([]*rbc.ItemRecord, error)
+	Close() error
+}
+
+// Query selects stored items. Zero fields are ignored; a nil query matches every item.
+type Query struct {
+	// From and To bound the server update timestamps to [From, To).
+	From time.Time
+	To   time.Time
+	// Rarities, Qualities and Destinations match any of their values.
+	Rarities     []rbc.Rarity
+	Qualities    []rbc.Quality
+	Destinations []rbc.Destination
+	// MinRarity matches items of the given rarity or above.
+	MinRarity rbc.Rarity
+	// Name matches items whose name contains it; case-insensitive.
+	Name string
+	// Bot matches items collected by the bot of that name; case-insensitive.
+	Bot string
+	// Limit is the maximum number of results; zero means no limit. Offset skips the first results.
+	Limit  int
+	Offset int
+}
+
+// allRarities are ordered from the least to the most rare.
+var allRarities = []rbc.Rarity{rbc.RarityNonAncient, rbc.RarityAncient, rbc.RarityPrimal}
+
+// RarityIn returns the rarities the query matches, or nil if it matches every rarity.
+func (q *Query) RarityIn() []rbc.Rarity {
+	if q == nil || (len(q.Rarities) == 0 && q.MinRarity == "") {
+		return nil
+	}
+	candidates := q.Rarities
+	if len(candidates) == 0 {
+		candidates = allRarities
+	}
+
+	rarities := []rbc.Rarity{}
+	atLeast := rbc.RarityAtLeast(q.MinRarity)
+	for _, r := range candidates {
+		if q.MinRarity == "" || atLeast(nil, &rbc.LegendaryItem{Rarity: r}) {
+			rarities = append(rarities, r)
+		}
+	}
+	return rarities
+}
+
+// Predicate returns the predicate equivalent to the query, but for `Limit` and `Offset`.
+func (q *Query) Predicate() rbc.Predicate {
+	if q == nil {
+		return func(*rbc.ServerUpdate, *rbc.LegendaryItem) bool { return true }
+	}
+
+	predicates := []rbc.Predicate{rbc.CollectedBetween(q.From, q.To)}
+	if rarities := q.RarityIn(); rarities != nil {
+		predicates = append(predicates, rbc.RarityIs(rarities...))
+	}
+	if len(q.Qualities) > 0 {
+		predicates = append(predicates, rbc.QualityIs(q.Qualities...))
+	}
+	if len(q.Destinations) > 0 {
+		predicates = append(predicates, rbc.DestinationIn(q.Destinations...))
+	}
+	if q.Name != "" {
+		predicates = append(predicates, rbc.NameContains(q.Name))
+	}
+	if q.Bot != "" {
+		predicates = append(predicates, rbc.BotIs(q.Bot))
+	}
+	return rbc.And(predicates...)
+}
+
+// Handler returns an update handler saving every collected server update to the store.
+func Handler(s Store) rbc.UpdateHandler {
+	return func(ctx context.Context, u *rbc.ServerUpdate) error {
+		return s.SaveUpdates(ctx, []*rbc.ServerUpdate{u})
+	}
+}
+
+// page applies the limit and offset to n results; it returns the bounds of the page.
+func (q *Query) page(n int) (int, int) {
+	if q == nil {
+		return 0, n
+	}
+	start := q.Offset
+	if start > n {
+		start = n
+	}
+	end := n
+	if q.Limit > 0 && start+q.Limit < n {
+		end = start + q.Limit
+	}
+	return start, end
+}
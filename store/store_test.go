@@ -0,0 +1,56 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+
+	rbc "github.com/maxzaleski/go-rosbot-collector"
+)
+
+func TestQuery_RarityIn(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *Query
+		want  []rbc.Rarity
+	}{
+		{name: "nil", query: nil, want: nil},
+		{name: "any", query: &Query{}, want: nil},
+		{name: "rarities", query: &Query{Rarities: []rbc.Rarity{rbc.RarityPrimal}}, want: []rbc.Rarity{rbc.RarityPrimal}},
+		{name: "min rarity", query: &Query{MinRarity: rbc.RarityAncient}, want: []rbc.Rarity{rbc.RarityAncient, rbc.RarityPrimal}},
+		{
+			name:  "disjoint",
+			query: &Query{Rarities: []rbc.Rarity{rbc.RarityNonAncient}, MinRarity: rbc.RarityPrimal},
+			want:  []rbc.Rarity{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.RarityIn(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RarityIn() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuery_page(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      *Query
+		n          int
+		start, end int
+	}{
+		{name: "nil", query: nil, n: 5, start: 0, end: 5},
+		{name: "limit", query: &Query{Limit: 2}, n: 5, start: 0, end: 2},
+		{name: "offset", query: &Query{Offset: 3}, n: 5, start: 3, end: 5},
+		{name: "limit offset", query: &Query{Limit: 3, Offset: 3}, n: 5, start: 3, end: 5},
+		{name: "offset beyond", query: &Query{Offset: 8}, n: 5, start: 5, end: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := tt.query.page(tt.n)
+			if start != tt.start || end != tt.end {
+				t.Errorf("page() = %d, %d, want %d, %d", start, end, tt.start, tt.end)
+			}
+		})
+	}
+}
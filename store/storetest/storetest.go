@@ -0,0 +1,254 @@
+// Package storetest checks the behaviour shared by every `store.Store` implementation.
+package storetest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	rbc "github.com/maxzaleski/go-rosbot-collector"
+	"github.com/maxzaleski/go-rosbot-collector/store"
+)
+
+// Run tests the store returned by `open`, which is called once per sub-test with an empty store.
+func Run(t *testing.T, open func(t *testing.T) store.Store) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, s store.Store)
+	}{
+		{name: "SaveUpdates", test: testSaveUpdates},
+		{name: "SaveUpdates replaces items", test: testReplaceItems},
+		{name: "SaveUpdates moves items", test: testMoveItems},
+		{name: "Item", test: testItem},
+		{name: "Items", test: testItems},
+		{name: "Updates", test: testUpdates},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := open(t)
+			defer s.Close()
+			tt.test(t, s)
+		})
+	}
+}
+
+// Updates returns the server updates saved by the tests, oldest first.
+func Updates() []*rbc.ServerUpdate {
+	paris, _ := time.LoadLocation("Europe/Paris")
+	ts := time.Date(2020, 5, 1, 12, 0, 0, 0, paris)
+	item := func(updateID string, index int, name string, q rbc.Quality, r rbc.Rarity, d rbc.Destination, bot string) *rbc.LegendaryItem {
+		return &rbc.LegendaryItem{
+			ID: updateID + "-" + string(rune('0'+index)), Index: index, Name: name, Quality: q, Rarity: r,
+			Destination: d, IsIdentified: name != "Unidentified", Stats: "+10 Strength\n+5% Critical Hit Chance", BotName: bot,
+		}
+	}
+	return []*rbc.ServerUpdate{
+		{
+			ID:               "u1",
+			ServerTimestamp:  ts,
+			DerivedTimestamp: ts.Add(13 * time.Second).UTC(),
+			UTCOffset:        2 * time.Hour,
+			RawTimestamp:     "05/01/2020 - 12:00",
+			RawRelativeTime:  "1 hour 2 min ago",
+			Items: []*rbc.LegendaryItem{
+				item("u1", 0, "Tyrael's Might", rbc.QualityNormal, rbc.RarityAncient, rbc.DestinationStashed, "Barb"),
+				item("u1", 1, "Unidentified", rbc.QualitySet, rbc.RarityNonAncient, rbc.DestinationSalvaged, "Barb"),
+			},
+		},
+		{
+			ID:              "u2",
+			ServerTimestamp: ts.Add(time.Hour),
+			Items: []*rbc.LegendaryItem{
+				item("u2", 0, "Stone of Jordan", rbc.QualityNormal, rbc.RarityPrimal, rbc.DestinationStashed, "Wiz"),
+			},
+		},
+		{
+			ID:           "u3",
+			RawTimestamp: "yesterday",
+			Items: []*rbc.LegendaryItem{
+				item("u3", 0, "Furnace", rbc.QualityNormal, rbc.RarityNonAncient, rbc.DestinationSold, "Wiz"),
+			},
+			Warnings: []*rbc.ParseWarning{{Field: "server_timestamp", Raw: "yesterday", Message: "invalid timestamp"}},
+		},
+	}
+}
+
+func save(t *testing.T, s store.Store, updates []*rbc.ServerUpdate) {
+	t.Helper()
+	if err := s.SaveUpdates(context.Background(), updates); err != nil {
+		t.Fatalf("SaveUpdates() error = %v", err)
+	}
+}
+
+// normalise converts timestamps to UTC, for comparisons across storage formats.
+func normalise(u *rbc.ServerUpdate) *rbc.ServerUpdate {
+	c := *u
+	c.ServerTimestamp = c.ServerTimestamp.UTC()
+	c.DerivedTimestamp = c.DerivedTimestamp.UTC()
+	if len(c.Warnings) == 0 {
+		c.Warnings = nil
+	}
+	return &c
+}
+
+func testSaveUpdates(t *testing.T, s store.Store) {
+	ctx := context.Background()
+	save(t, s, Updates())
+	// Saving twice is idempotent.
+	save(t, s, Updates())
+
+	for _, want := range Updates() {
+		got, err := s.Update(ctx, want.ID)
+		if err != nil {
+			t.Fatalf("Update(%q) error = %v", want.ID, err)
+		}
+		if !reflect.DeepEqual(normalise(got), normalise(want)) {
+			t.Errorf("Update(%q) = %+v, want %+v", want.ID, normalise(got), normalise(want))
+		}
+	}
+
+	if _, err := s.Update(ctx, "unknown"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Update(unknown) error = %v, want %v", err, store.ErrNotFound)
+	}
+}
+
+func testReplaceItems(t *testing.T, s store.Store) {
+	ctx := context.Background()
+	save(t, s, Updates())
+
+	u := Updates()[0]
+	u.Items = u.Items[:1]
+	u.Items[0].Destination = rbc.DestinationSold
+	save(t, s, []*rbc.ServerUpdate{u})
+
+	got, err := s.Update(ctx, u.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Items) != 1 || got.Items[0].Destination != rbc.DestinationSold {
+		t.Errorf("Update() items = %+v, want the replaced item only", got.Items)
+	}
+	if _, err := s.Item(ctx, "u1-1"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Item(u1-1) error = %v, want %v", err, store.ErrNotFound)
+	}
+}
+
+// testMoveItems saves an item under another update; item IDs do not depend on update IDs.
+func testMoveItems(t *testing.T, s store.Store) {
+	ctx := context.Background()
+	save(t, s, Updates())
+
+	u := Updates()[0]
+	u.ID = "u3"
+	u.Items = u.Items[1:]
+	save(t, s, []*rbc.ServerUpdate{u})
+
+	got, err := s.Item(ctx, "u1-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.UpdateID != "u3" {
+		t.Errorf("Item(u1-1) update = %q, want %q", got.UpdateID, "u3")
+	}
+	old, err := s.Update(ctx, "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(old.Items) != 1 || old.Items[0].ID != "u1-0" {
+		t.Errorf("Update(u1) items = %+v, want u1-0 only", old.Items)
+	}
+}
+
+func testItem(t *testing.T, s store.Store) {
+	ctx := context.Background()
+	save(t, s, Updates())
+
+	got, err := s.Item(ctx, "u2-0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Updates()[1]
+	if got.UpdateID != want.ID || !got.ServerTimestamp.Equal(want.ServerTimestamp) ||
+		!reflect.DeepEqual(got.LegendaryItem, want.Items[0]) {
+		t.Errorf("Item() = %+v %+v, want %+v", got, got.LegendaryItem, want.Items[0])
+	}
+
+	if _, err := s.Item(ctx, "u2-1"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Item(u2-1) error = %v, want %v", err, store.ErrNotFound)
+	}
+}
+
+func testItems(t *testing.T, s store.Store) {
+	save(t, s, Updates())
+	from := Updates()[0].ServerTimestamp
+
+	tests := []struct {
+		name  string
+		query *store.Query
+		want  []string
+	}{
+		// Updates without a timestamp come first.
+		{name: "all", query: nil, want: []string{"u3-0", "u1-0", "u1-1", "u2-0"}},
+		{name: "from", query: &store.Query{From: from.Add(time.Minute)}, want: []string{"u2-0"}},
+		{name: "to", query: &store.Query{From: from, To: from.Add(time.Hour)}, want: []string{"u1-0", "u1-1"}},
+		{name: "rarities", query: &store.Query{Rarities: []rbc.Rarity{rbc.RarityNonAncient, rbc.RarityPrimal}}, want: []string{"u3-0", "u1-1", "u2-0"}},
+		{name: "min rarity", query: &store.Query{MinRarity: rbc.RarityAncient}, want: []string{"u1-0", "u2-0"}},
+		{name: "qualities", query: &store.Query{Qualities: []rbc.Quality{rbc.QualitySet}}, want: []string{"u1-1"}},
+		{name: "destinations", query: &store.Query{Destinations: []rbc.Destination{rbc.DestinationSold, rbc.DestinationSalvaged}}, want: []string{"u3-0", "u1-1"}},
+		{name: "name", query: &store.Query{Name: "OF JOR"}, want: []string{"u2-0"}},
+		{name: "name wildcard", query: &store.Query{Name: "%"}, want: nil},
+		{name: "bot", query: &store.Query{Bot: "wiz"}, want: []string{"u3-0", "u2-0"}},
+		{name: "limit offset", query: &store.Query{Limit: 2, Offset: 1}, want: []string{"u1-0", "u1-1"}},
+		{name: "offset beyond", query: &store.Query{Offset: 10}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := s.Items(context.Background(), tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, r := range records {
+				got = append(got, r.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Items() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func testUpdates(t *testing.T, s store.Store) {
+	save(t, s, Updates())
+
+	tests := []struct {
+		name  string
+		query *store.Query
+		want  map[string]int
+		order []string
+	}{
+		{name: "all", query: nil, order: []string{"u3", "u1", "u2"}, want: map[string]int{"u3": 1, "u1": 2, "u2": 1}},
+		{name: "matching items only", query: &store.Query{Bot: "barb", Qualities: []rbc.Quality{rbc.QualityNormal}}, order: []string{"u1"}, want: map[string]int{"u1": 1}},
+		{name: "limit", query: &store.Query{Limit: 1, Offset: 1}, order: []string{"u1"}, want: map[string]int{"u1": 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updates, err := s.Updates(context.Background(), tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var order []string
+			for _, u := range updates {
+				order = append(order, u.ID)
+				if len(u.Items) != tt.want[u.ID] {
+					t.Errorf("Updates() %s has %d items, want %d", u.ID, len(u.Items), tt.want[u.ID])
+				}
+			}
+			if !reflect.DeepEqual(order, tt.order) {
+				t.Errorf("Updates() = %v, want %v", order, tt.order)
+			}
+		})
+	}
+}
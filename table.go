@@ -0,0 +1,51 @@
+package rosbotcollector
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// tableRow is a body row of a Drupal table, whose cells are looked up by header label, so that
+// parsing survives the site reordering its columns.
+type tableRow struct {
+	cells   *goquery.Selection
+	columns map[string]int
+}
+
+// eachTableRow calls `f` with every body row of the table. Rows spanning fewer cells than the
+// header, i.e. the "No entries" placeholder, are skipped.
+func eachTableRow(table *goquery.Selection, f func(r *tableRow)) {
+	columns := make(map[string]int)
+	table.Find("thead th").Each(func(i int, s *goquery.Selection) {
+		columns[strings.ToLower(cleanText(s.Text()))] = i
+	})
+
+	table.Find("tbody tr").Each(func(_ int, s *goquery.Selection) {
+		cells := s.ChildrenFiltered("td")
+		if cells.Length() < len(columns) {
+			return
+		}
+		f(&tableRow{cells: cells, columns: columns})
+	})
+}
+
+// cell returns the cell of the first matching header label, which is empty when none matches.
+func (r *tableRow) cell(labels ...string) *goquery.Selection {
+	for _, label := range labels {
+		if i, ok := r.columns[label]; ok {
+			return r.cells.Eq(i)
+		}
+	}
+	return r.cells.Slice(0, 0)
+}
+
+// text returns the trimmed text of the cell of the first matching header label.
+func (r *tableRow) text(labels ...string) string {
+	return cleanText(r.cell(labels...).Text())
+}
+
+// cleanText collapses the whitespace of the text of an element.
+func cleanText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
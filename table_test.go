@@ -0,0 +1,36 @@
+package rosbotcollector
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func Test_eachTableRow(t *testing.T) {
+	const html = `<table>
+		<thead><tr><th> Name </th><th>Last  modified</th></tr></thead>
+		<tbody>
+			<tr><td>Rift  farming</td><td>03/09/2019 - 21:58</td></tr>
+			<tr><td colspan="2" class="empty message">No profiles.</td></tr>
+			<tr><td>Bounties</td><td></td></tr>
+		</tbody>
+	</table>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got [][]string
+	eachTableRow(doc.Find("table"), func(r *tableRow) {
+		got = append(got, []string{r.text("name"), r.text("updated", "last modified"), r.text("missing")})
+	})
+	want := [][]string{
+		{"Rift farming", "03/09/2019 - 21:58", ""},
+		{"Bounties", "", ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("eachTableRow() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,234 @@
+package rosbotcollector
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type (
+	// Watchlist matches parsed legendary items against a list of items of interest.
+	Watchlist struct {
+		entries []*WatchEntry
+		// MaxDistance is the number of typos (Levenshtein distance) tolerated by the fuzzy match
+		// of names longer than `MaxDistance * 4` characters. Zero disables fuzzy matching.
+		MaxDistance int
+	}
+
+	// WatchEntry is a legendary item of interest.
+	WatchEntry struct {
+		// Name is the item name, as displayed by the site; case and punctuation are ignored.
+		Name string
+		// Aliases are alternative names, i.e. "tm" or "tyraels".
+		Aliases []string
+		// MinRarity is the minimum rarity of the item; empty means any.
+		MinRarity Rarity
+		// Stats are substrings which must all be present in the item stats; case-insensitive.
+		Stats []string
+	}
+
+	// WatchMatch is a parsed item which matched a watchlist entry.
+	WatchMatch struct {
+		Entry  *WatchEntry
+		Update *ServerUpdate
+		Item   *LegendaryItem
+		Kind   MatchKind
+		// MatchedName is the entry name, or alias, the item name matched.
+		MatchedName string
+		// Distance is the Levenshtein distance between the normalised names.
+		Distance int
+	}
+
+	// MatchKind is how the item name matched a watchlist entry.
+	MatchKind string
+)
+
+const (
+	MatchExact MatchKind = "EXACT"
+	MatchAlias MatchKind = "ALIAS"
+	MatchFuzzy MatchKind = "FUZZY"
+)
+
+// DefaultMaxDistance is the number of typos tolerated by a new watchlist.
+const DefaultMaxDistance = 2
+
+// NewWatchlist returns a new instance of `rosbotcollector.Watchlist`.
+func NewWatchlist(entries ...*WatchEntry) *Watchlist {
+	return &Watchlist{
+		entries:     entries,
+		MaxDistance: DefaultMaxDistance,
+	}
+}
+
+// Add appends entries to the watchlist.
+func (w *Watchlist) Add(entries ...*WatchEntry) {
+	w.entries = append(w.entries, entries...)
+}
+
+// Entries returns the entries of the watchlist.
+func (w *Watchlist) Entries() []*WatchEntry {
+	return w.entries
+}
+
+// Match returns a match per item of the server update which satisfies an entry.
+// An item matches at most one entry; the closest one.
+func (w *Watchlist) Match(u *ServerUpdate) []*WatchMatch {
+	var matches []*WatchMatch
+	for _, item := range u.Items {
+		if m := w.MatchItem(item); m != nil {
+			m.Update = u
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
+
+// MatchAll returns the matches of every server update.
+func (w *Watchlist) MatchAll(updates []*ServerUpdate) []*WatchMatch {
+	var matches []*WatchMatch
+	for _, u := range updates {
+		matches = append(matches, w.Match(u)...)
+	}
+	return matches
+}
+
+// MatchItem returns the closest entry the item satisfies, or nil.
+func (w *Watchlist) MatchItem(item *LegendaryItem) *WatchMatch {
+	// Unidentified items all share the same name.
+	if !item.IsIdentified {
+		return nil
+	}
+	name := normaliseName(item.Name)
+
+	var best *WatchMatch
+	for _, e := range w.entries {
+		m := w.matchName(e, name)
+		if m == nil || !e.satisfiedBy(item) {
+			continue
+		}
+		if best == nil || m.Distance < best.Distance {
+			m.Item = item
+			best = m
+		}
+	}
+	return best
+}
+
+// Predicate returns a predicate satisfied by the items matching the watchlist.
+func (w *Watchlist) Predicate() Predicate {
+	return func(_ *ServerUpdate, item *LegendaryItem) bool {
+		return w.MatchItem(item) != nil
+	}
+}
+
+func (w *Watchlist) matchName(e *WatchEntry, name string) *WatchMatch {
+	candidates := append([]string{e.Name}, e.Aliases...)
+	for i, c := range candidates {
+		if normaliseName(c) == name {
+			kind := MatchExact
+			if i > 0 {
+				kind = MatchAlias
+			}
+			return &WatchMatch{Entry: e, Kind: kind, MatchedName: c}
+		}
+	}
+
+	var best *WatchMatch
+	for _, c := range candidates {
+		n := normaliseName(c)
+		// Short names would match nearly anything.
+		if w.MaxDistance == 0 || len([]rune(n)) <= w.MaxDistance*4 {
+			continue
+		}
+		if d := levenshtein(n, name); d <= w.MaxDistance && (best == nil || d < best.Distance) {
+			best = &WatchMatch{Entry: e, Kind: MatchFuzzy, MatchedName: c, Distance: d}
+		}
+	}
+	return best
+}
+
+func (e *WatchEntry) satisfiedBy(item *LegendaryItem) bool {
+	if e.MinRarity != "" && item.Rarity.rank() < e.MinRarity.rank() {
+		return false
+	}
+	stats := strings.ToLower(item.Stats)
+	for _, s := range e.Stats {
+		if !strings.Contains(stats, strings.ToLower(s)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Reason describes why the item matched, i.e. `name "tyraels might" (fuzzy, distance 1)`.
+func (m *WatchMatch) Reason() string {
+	var b strings.Builder
+	switch m.Kind {
+	case MatchExact:
+		fmt.Fprintf(&b, "name %q", m.MatchedName)
+	case MatchAlias:
+		fmt.Fprintf(&b, "alias %q of %q", m.MatchedName, m.Entry.Name)
+	case MatchFuzzy:
+		fmt.Fprintf(&b, "name %q (fuzzy, distance %d)", m.MatchedName, m.Distance)
+	}
+	if m.Entry.MinRarity != "" {
+		fmt.Fprintf(&b, ", rarity %s >= %s", m.Item.Rarity, m.Entry.MinRarity)
+	}
+	for _, s := range m.Entry.Stats {
+		fmt.Fprintf(&b, ", stats contain %q", s)
+	}
+	return b.String()
+}
+
+func (m *WatchMatch) String() string {
+	return fmt.Sprintf("%s [%s] matched %s", m.Item.Name, m.Item.Rarity, m.Reason())
+}
+
+// normaliseName lowercases the name, drops punctuation and collapses whitespace;
+// "Tyrael's  Might" becomes "tyraels might".
+func normaliseName(name string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.TrimSpace(strings.ToLower(name)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			space = false
+		case unicode.IsSpace(r) && !space:
+			b.WriteRune(' ')
+			space = true
+		}
+	}
+	return b.String()
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,122 @@
+package rosbotcollector
+
+import (
+	"testing"
+)
+
+func TestWatchlist_Match(t *testing.T) {
+	tests := []struct {
+		name      string
+		entries   []*WatchEntry
+		wantItems []string
+		wantKinds []MatchKind
+	}{
+		{
+			name:      "Exact, case and punctuation insensitive",
+			entries:   []*WatchEntry{{Name: "Tyrael's Might"}},
+			wantItems: []string{"tyrael's might"},
+			wantKinds: []MatchKind{MatchExact},
+		},
+		{
+			name:      "Alias",
+			entries:   []*WatchEntry{{Name: "Captain Crimson's Trimmings", Aliases: []string{"tyraels might"}}},
+			wantItems: []string{"tyrael's might", "captain crimson's trimmings"},
+			wantKinds: []MatchKind{MatchAlias, MatchExact},
+		},
+		{
+			name:      "Fuzzy",
+			entries:   []*WatchEntry{{Name: "tyrail might"}},
+			wantItems: []string{"tyrael's might"},
+			wantKinds: []MatchKind{MatchFuzzy},
+		},
+		{
+			name:    "Too far",
+			entries: []*WatchEntry{{Name: "tyrannical might"}},
+		},
+		{
+			name:    "Below rarity",
+			entries: []*WatchEntry{{Name: "tyrael's might", MinRarity: RarityPrimal}},
+		},
+		{
+			name:      "Stats",
+			entries:   []*WatchEntry{{Name: "tyrael's might", MinRarity: RarityAncient, Stats: []string{"+474 DEXTERITY"}}},
+			wantItems: []string{"tyrael's might"},
+			wantKinds: []MatchKind{MatchExact},
+		},
+		{
+			name:    "Missing stats",
+			entries: []*WatchEntry{{Name: "tyrael's might", Stats: []string{"socket"}}},
+		},
+		{
+			name:    "Unidentified items are ignored",
+			entries: []*WatchEntry{{Name: "unidentified"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := NewWatchlist(tt.entries...).MatchAll(testUpdates())
+			if len(matches) != len(tt.wantItems) {
+				t.Fatalf("MatchAll() = %v, want %v", matches, tt.wantItems)
+			}
+			for i, m := range matches {
+				if m.Item.Name != tt.wantItems[i] || m.Kind != tt.wantKinds[i] {
+					t.Errorf("MatchAll()[%d] = %v (%v), want %v (%v)", i, m.Item.Name, m.Kind, tt.wantItems[i], tt.wantKinds[i])
+				}
+				if m.Update == nil || m.Reason() == "" {
+					t.Errorf("MatchAll()[%d] is incomplete: %+v", i, m)
+				}
+			}
+		})
+	}
+}
+
+func TestWatchlist_closestEntry(t *testing.T) {
+	w := NewWatchlist(
+		&WatchEntry{Name: "tyrael's mights"},
+		&WatchEntry{Name: "tyrael's might", MinRarity: RarityAncient},
+	)
+	m := w.MatchItem(testUpdates()[0].Items[0])
+	if m == nil || m.Entry.Name != "tyrael's might" {
+		t.Errorf("MatchItem() = %v, want the exact entry", m)
+	}
+	if want := `name "tyrael's might", rarity ANCIENT >= ANCIENT`; m.Reason() != want {
+		t.Errorf("Reason() = %v, want %v", m.Reason(), want)
+	}
+}
+
+func Test_normaliseName(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{raw: "Tyrael's  Might", want: "tyraels might"},
+		{raw: " Captain Crimson's Trimmings ", want: "captain crimsons trimmings"},
+		{raw: "Ring of Royal Grandeur", want: "ring of royal grandeur"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			if got := normaliseName(tt.raw); got != tt.want {
+				t.Errorf("normaliseName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_levenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "", b: "abc", want: 3},
+		{a: "kitten", b: "sitting", want: 3},
+		{a: "tyraels might", b: "tyrail might", want: 2},
+		{a: "same", b: "same", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := levenshtein(tt.a, tt.b); got != tt.want {
+				t.Errorf("levenshtein() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
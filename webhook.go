@@ -0,0 +1,234 @@
+package rosbotcollector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+type (
+	// Webhook is a destination of drop notifications.
+	Webhook struct {
+		URL string
+		// Template renders the JSON payload from a `WebhookPayload`; defaults to
+		// `DefaultWebhookTemplate`. See `NewWebhookTemplate`.
+		Template *template.Template
+		// Filter selects the items worth notifying; nil means every item.
+		Filter Predicate
+		// MinInterval is the minimum delay between two posts to the URL.
+		MinInterval time.Duration
+		// MaxRetries is the number of retries of a failed post; only network errors, '429 Too
+		// Many Requests' and 5xx responses are retried.
+		MaxRetries int
+		// RetryBackoff is the delay before the first retry; it doubles on every subsequent retry.
+		// It defaults to `DefaultRetryBackoff` when retries are enabled.
+		RetryBackoff time.Duration
+		// Header is added to every post, i.e. for authorisation.
+		Header http.Header
+
+		mu       sync.Mutex
+		lastPost time.Time
+	}
+
+	// WebhookPayload is the data rendered by a webhook template; the matched items of a single
+	// server update.
+	WebhookPayload struct {
+		Update *ServerUpdate
+		Items  []*LegendaryItem
+	}
+
+	// Notifier posts the matched items of every server update to its webhooks.
+	Notifier struct {
+		client *http.Client
+		hooks  []*Webhook
+	}
+)
+
+const (
+	// DefaultWebhookTemplate posts the server update and its matched items as JSON.
+	DefaultWebhookTemplate = `{"update_id": {{json .Update.ID}}, ` +
+		`"server_timestamp": {{json .Update.ServerTimestamp}}, "items": {{json .Items}}}`
+	// DiscordWebhookTemplate posts a message to a Discord channel webhook.
+	DiscordWebhookTemplate = `{"content": {{json (summary .)}}}`
+	// SlackWebhookTemplate posts a message to a Slack incoming webhook.
+	SlackWebhookTemplate = `{"text": {{json (summary .)}}}`
+)
+
+// DefaultRetryBackoff is the delay before the first retry of a webhook without `RetryBackoff`.
+const DefaultRetryBackoff = time.Second
+
+// ErrWebhookStatus is returned when a webhook responded with an unsuccessful status.
+var ErrWebhookStatus = errors.New("unsuccessful webhook response status")
+
+// WebhookFuncs are the functions available to webhook templates:
+//
+//	json    encodes its argument as JSON, i.e. {{json .Update.ID}}
+//	summary renders a human-readable message of the payload, one line per item
+func WebhookFuncs() template.FuncMap {
+	return template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+		"summary": func(p *WebhookPayload) string {
+			var b strings.Builder
+			for i, item := range p.Items {
+				if i > 0 {
+					b.WriteString("\n")
+				}
+				fmt.Fprintf(&b, "%s %s: %s [%s] %s", item.BotName, strings.ToLower(string(item.Destination)),
+					item.Name, strings.ToLower(string(item.Rarity)), strings.ToLower(string(item.Quality)))
+			}
+			return b.String()
+		},
+	}
+}
+
+// NewWebhookTemplate parses a payload template, with access to `WebhookFuncs`.
+func NewWebhookTemplate(text string) (*template.Template, error) {
+	return template.New("webhook").Funcs(WebhookFuncs()).Parse(text)
+}
+
+var defaultWebhookTemplate = template.Must(NewWebhookTemplate(DefaultWebhookTemplate))
+
+// NewNotifier returns a new instance of `rosbotcollector.Notifier`.
+// `http.DefaultClient` is used when `client` is nil.
+func NewNotifier(client *http.Client, hooks ...*Webhook) *Notifier {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Notifier{client: client, hooks: hooks}
+}
+
+// Notify posts the matched items of the server update to every webhook; once per webhook.
+// Webhooks without any matched item are skipped. It satisfies `UpdateHandler`.
+func (n *Notifier) Notify(ctx context.Context, u *ServerUpdate) error {
+	var (
+		first  error
+		failed int
+	)
+	for _, h := range n.hooks {
+		if err := n.notify(ctx, h, u); err != nil {
+			failed++
+			if first == nil {
+				first = fmt.Errorf("webhook %s: %w", h.URL, err)
+			}
+		}
+	}
+	if failed > 1 {
+		return fmt.Errorf("%w (and %d other webhooks)", first, failed-1)
+	}
+	return first
+}
+
+func (n *Notifier) notify(ctx context.Context, h *Webhook, u *ServerUpdate) error {
+	items := u.Items
+	if h.Filter != nil {
+		items = filter(items, func(item *LegendaryItem) bool { return h.Filter(u, item) })
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	tmpl := h.Template
+	if tmpl == nil {
+		tmpl = defaultWebhookTemplate
+	}
+	var payload bytes.Buffer
+	if err := tmpl.Execute(&payload, &WebhookPayload{Update: u, Items: items}); err != nil {
+		return err
+	}
+	// Precaution; a hand-written template may not escape its values.
+	if !json.Valid(payload.Bytes()) {
+		return fmt.Errorf("template rendered invalid JSON: %s", payload.String())
+	}
+
+	backoff := h.RetryBackoff
+	// Precaution; retrying without a delay would hammer a failing server.
+	if backoff <= 0 {
+		backoff = DefaultRetryBackoff
+	}
+	for attempt := 0; ; attempt++ {
+		retry, wait, err := n.post(ctx, h, payload.Bytes())
+		if err == nil || !retry || attempt >= h.MaxRetries {
+			return err
+		}
+		// The server's 'Retry-After' takes precedence over the backoff.
+		if wait == 0 {
+			wait = backoff
+			backoff *= 2
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// post reports whether a failed post should be retried, and after how long if the server said so.
+func (n *Notifier) post(ctx context.Context, h *Webhook, payload []byte) (bool, time.Duration, error) {
+	if err := h.waitTurn(ctx); err != nil {
+		return false, 0, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, h.URL, bytes.NewReader(payload))
+	if err != nil {
+		return false, 0, err
+	}
+	req = req.WithContext(ctx)
+	for k, v := range h.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := n.client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, 0, err
+	}
+	_, _ = io.Copy(ioutil.Discard, res.Body)
+	_ = res.Body.Close()
+
+	switch {
+	case res.StatusCode >= 200 && res.StatusCode < 300:
+		return false, 0, nil
+	case res.StatusCode == http.StatusTooManyRequests:
+		seconds, _ := strconv.Atoi(res.Header.Get("Retry-After"))
+		return true, time.Duration(seconds) * time.Second, fmt.Errorf("%w: %s", ErrWebhookStatus, res.Status)
+	default:
+		return res.StatusCode >= 500, 0, fmt.Errorf("%w: %s", ErrWebhookStatus, res.Status)
+	}
+}
+
+// waitTurn blocks until the webhook's rate limit allows a new post.
+func (h *Webhook) waitTurn(ctx context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if wait := time.Until(h.lastPost.Add(h.MinInterval)); wait > 0 {
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+	h.lastPost = time.Now()
+	return nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,197 @@
+package rosbotcollector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// webhookServer records the posted payloads, and responds with the scripted statuses in order.
+type webhookServer struct {
+	mu       sync.Mutex
+	statuses []int
+	payloads []string
+	times    []time.Time
+}
+
+func (s *webhookServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	body, _ := ioutil.ReadAll(r.Body)
+	s.payloads = append(s.payloads, string(body))
+	s.times = append(s.times, time.Now())
+
+	status := http.StatusNoContent
+	if len(s.statuses) > 0 {
+		status, s.statuses = s.statuses[0], s.statuses[1:]
+	}
+	if status == http.StatusTooManyRequests {
+		w.Header().Set("Retry-After", "0")
+	}
+	w.WriteHeader(status)
+}
+
+func TestNotifier_Notify(t *testing.T) {
+	discord, err := NewWebhookTemplate(DiscordWebhookTemplate)
+	if err != nil {
+		t.Fatalf("NewWebhookTemplate() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		hook      *Webhook
+		statuses  []int
+		wantPosts []string
+		wantErr   error
+	}{
+		{
+			name: "Batched per update",
+			hook: &Webhook{Template: discord},
+			wantPosts: []string{
+				`{"content": "Barbarian stashed: tyrael's might [ancient] normal\nBarbarian salvaged: unidentified [non-ancient] normal"}`,
+			},
+		},
+		{
+			name: "Filter",
+			hook: &Webhook{Template: discord, Filter: RarityAtLeast(RarityAncient)},
+			wantPosts: []string{
+				`{"content": "Barbarian stashed: tyrael's might [ancient] normal"}`,
+			},
+		},
+		{
+			name: "No match",
+			hook: &Webhook{Filter: RarityIs(RarityPrimal)},
+		},
+		{
+			name:     "Retried",
+			hook:     &Webhook{Template: discord, Filter: Identified(false), MaxRetries: 2, RetryBackoff: time.Millisecond},
+			statuses: []int{http.StatusBadGateway, http.StatusTooManyRequests, http.StatusOK},
+			wantPosts: []string{
+				`{"content": "Barbarian salvaged: unidentified [non-ancient] normal"}`,
+				`{"content": "Barbarian salvaged: unidentified [non-ancient] normal"}`,
+				`{"content": "Barbarian salvaged: unidentified [non-ancient] normal"}`,
+			},
+		},
+		{
+			name:     "Retries exhausted",
+			hook:     &Webhook{Template: discord, Filter: Identified(false), MaxRetries: 1, RetryBackoff: time.Millisecond},
+			statuses: []int{http.StatusInternalServerError, http.StatusInternalServerError},
+			wantPosts: []string{
+				`{"content": "Barbarian salvaged: unidentified [non-ancient] normal"}`,
+				`{"content": "Barbarian salvaged: unidentified [non-ancient] normal"}`,
+			},
+			wantErr: ErrWebhookStatus,
+		},
+		{
+			name:     "Client errors are not retried",
+			hook:     &Webhook{Template: discord, Filter: Identified(false), MaxRetries: 3},
+			statuses: []int{http.StatusBadRequest},
+			wantPosts: []string{
+				`{"content": "Barbarian salvaged: unidentified [non-ancient] normal"}`,
+			},
+			wantErr: ErrWebhookStatus,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &webhookServer{statuses: tt.statuses}
+			server := httptest.NewServer(s)
+			defer server.Close()
+
+			tt.hook.URL = server.URL
+			err := NewNotifier(server.Client(), tt.hook).Notify(context.Background(), testUpdates()[0])
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Notify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(s.payloads) != len(tt.wantPosts) {
+				t.Fatalf("Notify() posted %v, want %v", s.payloads, tt.wantPosts)
+			}
+			for i := range s.payloads {
+				if s.payloads[i] != tt.wantPosts[i] {
+					t.Errorf("Notify() posted %v, want %v", s.payloads[i], tt.wantPosts[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNotifier_defaultTemplate(t *testing.T) {
+	s := &webhookServer{}
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	u := testUpdates()[1]
+	u.ID = "0123456789abcdef"
+	if err := NewNotifier(server.Client(), &Webhook{URL: server.URL}).Notify(context.Background(), u); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	var got struct {
+		UpdateID        string           `json:"update_id"`
+		ServerTimestamp time.Time        `json:"server_timestamp"`
+		Items           []*LegendaryItem `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(s.payloads[0]), &got); err != nil {
+		t.Fatalf("Notify() posted invalid JSON: %v", err)
+	}
+	if got.UpdateID != u.ID || !got.ServerTimestamp.Equal(u.ServerTimestamp) || len(got.Items) != 1 {
+		t.Errorf("Notify() posted %+v", got)
+	}
+}
+
+func TestNotifier_rateLimit(t *testing.T) {
+	s := &webhookServer{}
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	n := NewNotifier(server.Client(), &Webhook{URL: server.URL, MinInterval: 50 * time.Millisecond})
+	start := time.Now()
+	for _, u := range testUpdates() {
+		if err := n.Notify(context.Background(), u); err != nil {
+			t.Fatalf("Notify() error = %v", err)
+		}
+	}
+	if len(s.times) != 2 {
+		t.Fatalf("Notify() posted %d times, want 2", len(s.times))
+	}
+	// The interval runs from the start of a post, whose latency the server cannot tell apart.
+	if d := s.times[1].Sub(start); d < 50*time.Millisecond {
+		t.Errorf("Notify() posted the second update %v after the first call, want at least 50ms", d)
+	}
+}
+
+func TestNotifier_defaultBackoff(t *testing.T) {
+	s := &webhookServer{statuses: []int{http.StatusInternalServerError}}
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	// Without a backoff, the retry would be immediate; the default one outlasts the context.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	n := NewNotifier(server.Client(), &Webhook{URL: server.URL, MaxRetries: 1})
+	if err := n.Notify(ctx, testUpdates()[0]); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Notify() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if len(s.payloads) != 1 {
+		t.Errorf("Notify() posted %d times, want 1", len(s.payloads))
+	}
+}
+
+func TestNewWebhookTemplate_invalidJSON(t *testing.T) {
+	tmpl, err := NewWebhookTemplate(`{"content": "{{summary .}}"}`)
+	if err != nil {
+		t.Fatalf("NewWebhookTemplate() error = %v", err)
+	}
+	err = NewNotifier(nil, &Webhook{URL: "http://127.0.0.1:0", Template: tmpl}).
+		Notify(context.Background(), testUpdates()[0])
+	if err == nil {
+		t.Errorf("Notify() expected an error")
+	}
+}